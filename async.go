@@ -0,0 +1,255 @@
+package noodlog
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy tells what an asynchronous logger does with a record when its buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until there's room in the buffer
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the record being logged
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest record in the buffer to make room
+	OverflowDropOldest
+)
+
+// defaultAsyncBufferSize is the number of records buffered when no size is specified
+const defaultAsyncBufferSize = 1024
+
+// AsyncOptions struct contains the settings of the asynchronous logging
+type AsyncOptions struct {
+	BufferSize     int
+	OverflowPolicy OverflowPolicy
+}
+
+// withDefaults returns the options with the default values in place of the unset ones
+func (o AsyncOptions) withDefaults() AsyncOptions {
+	if o.BufferSize <= 0 {
+		o.BufferSize = defaultAsyncBufferSize
+	}
+	return o
+}
+
+// asyncQueue queues the records into a bounded buffer written to their writers by a background goroutine
+type asyncQueue struct {
+	options AsyncOptions
+	queue   chan asyncRecord
+	done    chan struct{}
+	dropped uint64
+
+	// closeMu prevents Close from closing the queue while a record is being sent
+	closeMu sync.RWMutex
+	closed  bool
+
+	// pendingMu guards the number of records queued and not yet written, signaling when it gets to zero
+	pendingMu sync.Mutex
+	pending   int
+	flushed   *sync.Cond
+}
+
+// asyncRecord is a record waiting to be written to its writer
+type asyncRecord struct {
+	w   io.Writer
+	rec []byte
+}
+
+// asyncWriter is the io.Writer which queues the records for w
+type asyncWriter struct {
+	q *asyncQueue
+	w io.Writer
+}
+
+func newAsyncQueue(options AsyncOptions) *asyncQueue {
+	options = options.withDefaults()
+	q := &asyncQueue{
+		options: options,
+		queue:   make(chan asyncRecord, options.BufferSize),
+		done:    make(chan struct{}),
+	}
+	q.flushed = sync.NewCond(&q.pendingMu)
+	go q.run()
+	return q
+}
+
+// writer returns an io.Writer which queues the records for w
+func (q *asyncQueue) writer(w io.Writer) io.Writer {
+	return asyncWriter{q: q, w: w}
+}
+
+// Write queues the record for the writer
+func (a asyncWriter) Write(p []byte) (int, error) {
+	return a.q.write(a.w, p)
+}
+
+// run writes the queued records until the queue is closed
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for r := range q.queue {
+		_, _ = r.w.Write(r.rec)
+		q.release()
+	}
+}
+
+// write queues a copy of p according to the overflow policy; once closed it writes synchronously
+func (q *asyncQueue) write(w io.Writer, p []byte) (int, error) {
+	q.closeMu.RLock()
+	defer q.closeMu.RUnlock()
+	if q.closed {
+		return w.Write(p)
+	}
+
+	r := asyncRecord{w: w, rec: append(make([]byte, 0, len(p)), p...)}
+	q.acquire()
+	switch q.options.OverflowPolicy {
+	case OverflowDropNewest:
+		select {
+		case q.queue <- r:
+		default:
+			q.drop()
+		}
+	case OverflowDropOldest:
+		for sent := false; !sent; {
+			select {
+			case q.queue <- r:
+				sent = true
+			default:
+				select {
+				case <-q.queue:
+					q.drop()
+				default:
+				}
+			}
+		}
+	default:
+		q.queue <- r
+	}
+	return len(p), nil
+}
+
+// Flush waits until every queued record has been written
+func (q *asyncQueue) Flush() {
+	q.pendingMu.Lock()
+	defer q.pendingMu.Unlock()
+	for q.pending > 0 {
+		q.flushed.Wait()
+	}
+}
+
+// Close writes the queued records and stops the background goroutine, the writers are left open
+func (q *asyncQueue) Close() {
+	q.closeMu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.queue)
+	}
+	q.closeMu.Unlock()
+	<-q.done
+}
+
+// Dropped returns the number of records discarded because the buffer was full
+func (q *asyncQueue) Dropped() uint64 {
+	return atomic.LoadUint64(&q.dropped)
+}
+
+func (q *asyncQueue) acquire() {
+	q.pendingMu.Lock()
+	q.pending++
+	q.pendingMu.Unlock()
+}
+
+func (q *asyncQueue) release() {
+	q.pendingMu.Lock()
+	q.pending--
+	if q.pending == 0 {
+		q.flushed.Broadcast()
+	}
+	q.pendingMu.Unlock()
+}
+
+func (q *asyncQueue) drop() {
+	atomic.AddUint64(&q.dropped, 1)
+	q.release()
+}
+
+// EnableAsync lets the logger queue the records into a bounded buffer written by a background goroutine,
+// so that a slow log writer doesn't stall the callers. Call Close before exiting to deliver the queued records.
+func (l *Logger) EnableAsync(options AsyncOptions) *Logger {
+	return l.update(func(s *settings) {
+		s.setAsync(&options)
+	})
+}
+
+// DisableAsync writes the queued records and lets the logger write synchronously again
+func (l *Logger) DisableAsync() *Logger {
+	return l.update(func(s *settings) {
+		s.setAsync(nil)
+	})
+}
+
+// Flush waits until the records queued by an asynchronous logger have been written
+func (l *Logger) Flush() {
+	l.mu.RLock()
+	async := l.async
+	l.mu.RUnlock()
+
+	if async != nil {
+		async.Flush()
+	}
+}
+
+// Close writes the records queued by an asynchronous logger and stops its background goroutine.
+// The logger keeps working synchronously after Close.
+// The loggers derived with With or Named share the queue of their parent: Close on them only makes them
+// write synchronously, while the queue is closed by the logger which enabled the asynchronous logging.
+func (l *Logger) Close() {
+	l.DisableAsync()
+}
+
+// DroppedRecords returns the number of records discarded by an asynchronous logger because its buffer was full
+func (l *Logger) DroppedRecords() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.async == nil {
+		return l.dropped
+	}
+	return l.dropped + l.async.Dropped()
+}
+
+// setAsync replaces the asynchronous queue, closing the current one when owned by the logger;
+// nil options disable the asynchronous logging
+func (s *settings) setAsync(options *AsyncOptions) {
+	if s.async != nil && s.ownsAsync {
+		s.async.Close()
+		s.dropped += s.async.Dropped()
+	}
+	s.async, s.ownsAsync = nil, false
+	if options != nil {
+		s.async, s.ownsAsync = newAsyncQueue(*options), true
+	}
+}
+
+// updateAsync replaces the asynchronous queue only when the options differ from the current ones, so that the
+// loggers derived from this one keep sharing its queue when the same configs are applied again
+func (s *settings) updateAsync(options *AsyncOptions) {
+	if options == nil && s.async == nil {
+		return
+	}
+	if options != nil && s.async != nil && s.async.options == options.withDefaults() {
+		return
+	}
+	s.setAsync(options)
+}
+
+// writerFor returns the writer to be used for w: the asynchronous one when enabled
+func (s *settings) writerFor(w io.Writer) io.Writer {
+	if s.async != nil {
+		return s.async.writer(w)
+	}
+	return w
+}
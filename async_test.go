@@ -0,0 +1,132 @@
+package noodlog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// slowWriter blocks every write until it is released
+type slowWriter struct {
+	mu      sync.Mutex
+	b       bytes.Buffer
+	release chan struct{}
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+func (w *slowWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.String()
+}
+
+func TestAsyncFlushAndClose(t *testing.T) {
+	w := &slowWriter{release: make(chan struct{})}
+	close(w.release)
+	l := NewLogger().LogWriter(w).EnableAsync(AsyncOptions{BufferSize: 2})
+
+	for i := 0; i < 10; i++ {
+		l.Info("record", i)
+	}
+	l.Flush()
+	if lines := strings.Count(w.String(), "\n"); lines != 10 {
+		t.Errorf(errorFmt, "TestAsyncFlushAndClose", 10, lines)
+	}
+
+	l.Info("last record")
+	l.Close()
+	if actual := w.String(); !strings.Contains(actual, "last record") {
+		t.Errorf(errorFmt, "TestAsyncFlushAndClose", "last record", actual)
+	}
+
+	l.Info("after close")
+	if actual := w.String(); !strings.Contains(actual, "after close") {
+		t.Errorf(errorFmt, "TestAsyncFlushAndClose", "after close", actual)
+	}
+}
+
+func TestAsyncChildClose(t *testing.T) {
+	w := &slowWriter{release: make(chan struct{})}
+	close(w.release)
+	parent := NewLogger().LogWriter(w).EnableAsync(AsyncOptions{BufferSize: 2})
+	defer parent.Close()
+
+	child := parent.With("requestId", "abc")
+	child.Info("from the child")
+	child.Close()
+	if parent.async == nil || parent.async.closed {
+		t.Fatalf(errorFmt, "TestAsyncChildClose", "the parent queue open", "closed")
+	}
+
+	parent.Info("from the parent")
+	child.Info("child after close")
+	parent.Flush()
+	for _, expected := range []string{"from the child", "from the parent", "child after close"} {
+		if actual := w.String(); !strings.Contains(actual, expected) {
+			t.Errorf(errorFmt, "TestAsyncChildClose", expected, actual)
+		}
+	}
+}
+
+func TestAsyncOverflowPolicies(t *testing.T) {
+	testMap := map[OverflowPolicy]bool{
+		OverflowDropNewest: false,
+		OverflowDropOldest: true,
+	}
+
+	for policy, keepsNewest := range testMap {
+		w := &slowWriter{release: make(chan struct{})}
+		a := newAsyncQueue(AsyncOptions{BufferSize: 1, OverflowPolicy: policy})
+
+		l := NewLogger().LogWriter(a.writer(w))
+		for i := 0; i < 10; i++ {
+			l.Info("record", i)
+		}
+		close(w.release)
+		a.Close()
+
+		if a.Dropped() == 0 {
+			t.Errorf(errorFmt, "TestAsyncOverflowPolicies", "dropped records", a.Dropped())
+		}
+		if actual := w.String(); strings.Contains(actual, `"message":"record 9"`) != keepsNewest {
+			t.Errorf(errorFmt, "TestAsyncOverflowPolicies", keepsNewest, actual)
+		}
+		if written := uint64(strings.Count(w.String(), "\n")); written+a.Dropped() != 10 {
+			t.Errorf(errorFmt, "TestAsyncOverflowPolicies", 10, written+a.Dropped())
+		}
+	}
+}
+
+func TestAsyncConfigs(t *testing.T) {
+	var b1, b2 bytes.Buffer
+	l := NewLogger().SetConfigs(Configs{
+		LogWriter:    &b1,
+		Async:        Enable,
+		AsyncOptions: &AsyncOptions{BufferSize: 10, OverflowPolicy: OverflowDropNewest},
+	})
+	if l.async == nil || l.async.options.BufferSize != 10 || l.async.options.OverflowPolicy != OverflowDropNewest {
+		t.Fatalf(errorFmt, "TestAsyncConfigs", "async writer with the given options", l.async)
+	}
+
+	l.Info("first")
+	l.LogWriter(&b2)
+	l.Info("second")
+	l.SetConfigs(Configs{Async: Disable})
+
+	if l.async != nil {
+		t.Errorf(errorFmt, "TestAsyncConfigs", nil, l.async)
+	}
+	if !strings.Contains(b1.String(), "first") || !strings.Contains(b2.String(), "second") {
+		t.Errorf(errorFmt, "TestAsyncConfigs", "records on both writers", b1.String()+b2.String())
+	}
+	if l.DroppedRecords() != 0 {
+		t.Errorf(errorFmt, "TestAsyncConfigs", 0, l.DroppedRecords())
+	}
+}
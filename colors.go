@@ -34,6 +34,15 @@ var backgroundColors = map[string]string{
 	cyanColor:    backgroundCyan,
 }
 
+// copyColorMap returns a copy of the given color map, so that every logger owns its colors
+func copyColorMap(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
 // isValidColor check if a true color is valid, it has to be included between 0 and 255
 func isValidColor(color int) bool {
 	return color >= 0 && color <= 255
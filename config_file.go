@@ -0,0 +1,459 @@
+package noodlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fileConfigs is the representation of the Configs in the JSON and YAML files
+type fileConfigs struct {
+	LogLevel             *string           `json:"logLevel,omitempty"`
+	LevelOverrides       map[string]string `json:"levelOverrides,omitempty"`
+	Output               *string           `json:"output,omitempty"`
+	Writer               string            `json:"writer,omitempty"`
+	Format               *string           `json:"format,omitempty"`
+	Preset               *string           `json:"preset,omitempty"`
+	TimeFormat           *string           `json:"timeFormat,omitempty"`
+	Schema               *Schema           `json:"schema,omitempty"`
+	UTC                  *bool             `json:"utc,omitempty"`
+	JSONPrettyPrint      *bool             `json:"jsonPrettyPrint,omitempty"`
+	TraceCaller          *bool             `json:"traceCaller,omitempty"`
+	SinglePointTracing   *bool             `json:"singlePointTracing,omitempty"`
+	Colors               *bool             `json:"colors,omitempty"`
+	CustomColors         fileColors        `json:"customColors,omitempty"`
+	ObscureSensitiveData *bool             `json:"obscureSensitiveData,omitempty"`
+	SensitiveParams      []string          `json:"sensitiveParams,omitempty"`
+	DetectSecrets        *bool             `json:"detectSecrets,omitempty"`
+	Async                *bool             `json:"async,omitempty"`
+	Sinks                []fileSink        `json:"sinks,omitempty"`
+}
+
+// fileSink is the representation of a Sink in the files
+type fileSink struct {
+	Output               *string  `json:"output,omitempty"`
+	Writer               string   `json:"writer,omitempty"`
+	Level                *string  `json:"level,omitempty"`
+	Format               *string  `json:"format,omitempty"`
+	JSONPrettyPrint      *bool    `json:"jsonPrettyPrint,omitempty"`
+	Colors               *bool    `json:"colors,omitempty"`
+	ObscureSensitiveData *bool    `json:"obscureSensitiveData,omitempty"`
+	SensitiveParams      []string `json:"sensitiveParams,omitempty"`
+}
+
+// fileColors contains the custom colors of the files by level name, built-in or registered
+type fileColors map[string]*fileColor
+
+// fileColor is a color in the files: a name ("red"), a hex RGB string ("#ff8800"), an RGB object ({"r":255,"g":136,"b":0})
+// or the ANSI escape code of a composed color ("\u001b[31m\u001b[43m")
+type fileColor struct {
+	color interface{}
+}
+
+// LoadConfigs function reads the Configs from a JSON file or, when its extension is .yaml or .yml, from a YAML file.
+// Only a subset of YAML is supported: block mappings and sequences, flow sequences, scalars and comments.
+// The configs are validated, see Configs.Validate.
+func LoadConfigs(filename string) (Configs, error) {
+	fc, err := loadFileConfigs(filename)
+	if err != nil {
+		return Configs{}, err
+	}
+	configs := fc.configs()
+	if err := configs.Validate(); err != nil {
+		return Configs{}, fmt.Errorf("%w (in %s)", err, filename)
+	}
+	return configs, nil
+}
+
+// loadFileConfigs reads and decodes a configs file
+func loadFileConfigs(filename string) (fileConfigs, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fileConfigs{}, fmt.Errorf("noodlog: %w", err)
+	}
+	if ext := strings.ToLower(filepath.Ext(filename)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yamlToJSON(data); err != nil {
+			return fileConfigs{}, fmt.Errorf("noodlog: %s: %w", filename, err)
+		}
+	}
+	var fc fileConfigs
+	if err := fc.decode(data); err != nil {
+		return fileConfigs{}, fmt.Errorf("noodlog: %s: %w", filename, err)
+	}
+	return fc, nil
+}
+
+// UnmarshalJSON decodes the Configs from the representation used by the files, e.g.
+// {"logLevel":"debug","format":"logfmt","output":"stderr","customColors":{"warn":"#ff8800"}}.
+// The unknown keys and the invalid sinks are reported as errors, while the other values are checked by Validate.
+// The "writer" key, which describes a log writer other than stdout, stderr or a file, is ignored, as well as the
+// sinks described by it.
+func (c *Configs) UnmarshalJSON(data []byte) error {
+	var fc fileConfigs
+	if err := fc.decode(data); err != nil {
+		return fmt.Errorf("noodlog: %w", err)
+	}
+	*c = fc.configs()
+	return nil
+}
+
+// MarshalJSON encodes the Configs with the representation used by the files. The log writer is encoded as the
+// "output" key when it's stdout, stderr or a FileWriter, and described by the "writer" key otherwise. The sinks
+// are encoded the same way in place of the log writer, which they replace. The encoders, when not built-in, the clock,
+// the redactions, the detectors and the context extractors are not encoded.
+func (c Configs) MarshalJSON() ([]byte, error) {
+	fc := fileConfigs{
+		LogLevel:             c.LogLevel,
+		LevelOverrides:       c.LevelOverrides,
+		Format:               c.Format,
+		Preset:               c.Preset,
+		TimeFormat:           c.TimeFormat,
+		Schema:               c.Schema,
+		UTC:                  c.UTC,
+		JSONPrettyPrint:      c.JSONPrettyPrint,
+		TraceCaller:          c.TraceCaller,
+		SinglePointTracing:   c.SinglePointTracing,
+		Colors:               c.Colors,
+		ObscureSensitiveData: c.ObscureSensitiveData,
+		SensitiveParams:      c.SensitiveParams,
+		DetectSecrets:        c.DetectSecrets,
+		Async:                c.Async,
+	}
+	for _, sink := range c.Sinks {
+		if sink != nil && sink.writer != nil {
+			fc.Sinks = append(fc.Sinks, newFileSink(sink))
+		}
+	}
+	if len(fc.Sinks) == 0 {
+		// the log writer is ignored by the loggers with sinks
+		fc.Output, fc.Writer = describeWriter(c.LogWriter)
+	}
+	if cc := c.CustomColors; cc != nil {
+		colors := fileColors{}
+		for label, color := range cc.Levels {
+			colors[label] = newFileColor(color)
+		}
+		builtin := map[string]interface{}{traceLabel: cc.Trace, debugLabel: cc.Debug, infoLabel: cc.Info, warnLabel: cc.Warn, errorLabel: cc.Error}
+		for label, color := range builtin {
+			colors[label] = newFileColor(color)
+		}
+		for label, color := range colors {
+			if color == nil {
+				delete(colors, label)
+			}
+		}
+		fc.CustomColors = colors
+	}
+	return json.Marshal(fc)
+}
+
+// describeWriter returns the output of a writer when it's stdout, stderr or a FileWriter, and its type otherwise
+func describeWriter(w io.Writer) (output *string, writer string) {
+	switch w := w.(type) {
+	case nil:
+	case *FileWriter:
+		output = pointerOfString(w.filename)
+	default:
+		if w == os.Stdout {
+			output = pointerOfString("stdout")
+		} else if w == os.Stderr {
+			output = pointerOfString("stderr")
+		} else {
+			writer = fmt.Sprintf("%T", w)
+		}
+	}
+	return output, writer
+}
+
+// newFileSink returns the file representation of a sink
+func newFileSink(sink *Sink) fileSink {
+	options := sink.Options()
+	fs := fileSink{
+		Level:                options.Level,
+		Format:               options.Format,
+		JSONPrettyPrint:      options.JSONPrettyPrint,
+		Colors:               options.Colors,
+		ObscureSensitiveData: options.ObscureSensitiveData,
+		SensitiveParams:      options.SensitiveParams,
+	}
+	fs.Output, fs.Writer = describeWriter(sink.writer)
+	return fs
+}
+
+// decode unmarshals the JSON data, rejecting the unknown keys and the invalid sinks
+func (fc *fileConfigs) decode(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(fc); err != nil {
+		return err
+	}
+	for i, sink := range fc.Sinks {
+		if sink.Level != nil {
+			if _, err := ParseLevel(*sink.Level); err != nil {
+				return fmt.Errorf("sinks[%d]: unknown level %q", i, *sink.Level)
+			}
+		}
+		if sink.Format != nil && getEncoder(*sink.Format) == nil {
+			return fmt.Errorf("sinks[%d]: unknown format %q, expected json, logfmt or console", i, *sink.Format)
+		}
+		if sink.Output == nil && sink.Writer == "" {
+			return fmt.Errorf("sinks[%d]: the output is required", i)
+		}
+	}
+	return nil
+}
+
+// configs converts the file representation into the Configs
+func (fc fileConfigs) configs() Configs {
+	configs := Configs{
+		LogLevel:             fc.LogLevel,
+		LevelOverrides:       fc.LevelOverrides,
+		Format:               fc.Format,
+		Preset:               fc.Preset,
+		TimeFormat:           fc.TimeFormat,
+		Schema:               fc.Schema,
+		UTC:                  fc.UTC,
+		JSONPrettyPrint:      fc.JSONPrettyPrint,
+		TraceCaller:          fc.TraceCaller,
+		SinglePointTracing:   fc.SinglePointTracing,
+		Colors:               fc.Colors,
+		ObscureSensitiveData: fc.ObscureSensitiveData,
+		SensitiveParams:      fc.SensitiveParams,
+		DetectSecrets:        fc.DetectSecrets,
+		Async:                fc.Async,
+	}
+	if fc.Output != nil {
+		configs.LogWriter = outputWriter(*fc.Output)
+	}
+	if fc.Sinks != nil {
+		configs.Sinks = []*Sink{}
+		for _, sink := range fc.Sinks {
+			if sink.Output == nil {
+				// described by its writer type, which can't be built
+				continue
+			}
+			configs.Sinks = append(configs.Sinks, NewSinkWithOptions(outputWriter(*sink.Output), SinkOptions{
+				Level:                sink.Level,
+				Format:               sink.Format,
+				JSONPrettyPrint:      sink.JSONPrettyPrint,
+				Colors:               sink.Colors,
+				ObscureSensitiveData: sink.ObscureSensitiveData,
+				SensitiveParams:      sink.SensitiveParams,
+			}))
+		}
+		if len(configs.Sinks) == 0 && len(fc.Sinks) > 0 {
+			configs.Sinks = nil
+		}
+	}
+	if fc.CustomColors != nil {
+		configs.CustomColors = &CustomColors{}
+		for name, color := range fc.CustomColors {
+			switch label := strings.ToLower(name); label {
+			case traceLabel:
+				configs.CustomColors.Trace = color.value()
+			case debugLabel:
+				configs.CustomColors.Debug = color.value()
+			case infoLabel:
+				configs.CustomColors.Info = color.value()
+			case warnLabel:
+				configs.CustomColors.Warn = color.value()
+			case errorLabel:
+				configs.CustomColors.Error = color.value()
+			default:
+				if configs.CustomColors.Levels == nil {
+					configs.CustomColors.Levels = map[string]interface{}{}
+				}
+				configs.CustomColors.Levels[label] = color.value()
+			}
+		}
+	}
+	return configs
+}
+
+// outputWriter returns the writer of an output: stdout, stderr or the path of a file
+func outputWriter(output string) io.Writer {
+	switch strings.ToLower(output) {
+	case "stdout":
+		return os.Stdout
+	case "stderr":
+		return os.Stderr
+	default:
+		return NewFileWriter(output, FileWriterOptions{})
+	}
+}
+
+// UnmarshalJSON decodes a color name, a hex RGB string, an RGB object or an ANSI escape code
+func (c *fileColor) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		switch {
+		case strings.HasPrefix(name, "\033["):
+			c.color = Color{Code: &name}
+			return nil
+		case strings.ToLower(name) == defaultColor:
+			code := colorReset
+			c.color = Color{Code: &code}
+			return nil
+		case !strings.HasPrefix(name, "#"):
+			c.color = pointerOfString(strings.ToLower(name))
+			return nil
+		}
+		rgb, err := strconv.ParseUint(name[1:], 16, 32)
+		if err != nil || len(name) != 7 {
+			return fmt.Errorf("invalid color %q, expected #rrggbb", name)
+		}
+		c.color = NewColorRGB(int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff))
+		return nil
+	}
+
+	var rgb struct{ R, G, B *int }
+	if err := json.Unmarshal(data, &rgb); err != nil || rgb.R == nil || rgb.G == nil || rgb.B == nil {
+		return fmt.Errorf("invalid color %s, expected a name, #rrggbb or {\"r\":0,\"g\":0,\"b\":0}", data)
+	}
+	if !isValidColor(*rgb.R) || !isValidColor(*rgb.G) || !isValidColor(*rgb.B) {
+		return fmt.Errorf("invalid color %s, the components must be between 0 and 255", data)
+	}
+	c.color = NewColorRGB(*rgb.R, *rgb.G, *rgb.B)
+	return nil
+}
+
+// newFileColor returns the file representation of a color of CustomColors, nil if unset or unsupported
+func newFileColor(color interface{}) *fileColor {
+	switch color.(type) {
+	case Color, *string:
+		return &fileColor{color: color}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the color as a name, a hex RGB string or the ANSI escape code of a composed color
+func (c fileColor) MarshalJSON() ([]byte, error) {
+	if name, ok := c.color.(*string); ok {
+		return json.Marshal(name)
+	}
+	code := detectColor(c.color).toCode()
+	if code == colorReset {
+		return json.Marshal(defaultColor)
+	}
+	for name, colorCode := range colors {
+		if code == "\033["+colorCode+"m" {
+			return json.Marshal(name)
+		}
+	}
+	var r, g, b int
+	if _, err := fmt.Sscanf(code, "\033[38;2;%d;%d;%dm", &r, &g, &b); err == nil && code == NewColorRGB(r, g, b).toCode() {
+		return json.Marshal(fmt.Sprintf("#%02x%02x%02x", r, g, b))
+	}
+	return json.Marshal(code)
+}
+
+// value returns the color as accepted by CustomColors, nil if unset
+func (c *fileColor) value() interface{} {
+	if c == nil {
+		return nil
+	}
+	return c.color
+}
+
+// Validate checks the Configs and returns an error describing all the invalid values,
+// which SetConfigs would otherwise ignore or replace with their defaults
+func (c Configs) Validate() error {
+	var problems []string
+	invalid := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.LogLevel != nil {
+		if _, err := ParseLevel(*c.LogLevel); err != nil {
+			invalid("LogLevel: unknown level %q", *c.LogLevel)
+		}
+	}
+	for pattern, level := range c.LevelOverrides {
+		if _, err := pathpkg.Match(pattern, ""); err != nil || pattern == "" {
+			invalid("LevelOverrides: invalid pattern %q", pattern)
+		}
+		if _, err := ParseLevel(level); err != nil {
+			invalid("LevelOverrides: unknown level %q for %q", level, pattern)
+		}
+	}
+	if c.Format != nil && getEncoder(*c.Format) == nil {
+		invalid("Format: unknown format %q, expected json, logfmt or console", *c.Format)
+	}
+	if c.Preset != nil {
+		if _, ok := presets[strings.ToLower(*c.Preset)]; !ok {
+			invalid("Preset: unknown preset %q, expected gcp, ecs or datadog", *c.Preset)
+		}
+	}
+	if c.Schema != nil {
+		switch LevelFormat(strings.ToLower(string(c.Schema.LevelFormat))) {
+		case "", LowercaseLevels, UppercaseLevels, NumericLevels:
+		default:
+			invalid("Schema.LevelFormat: unknown format %q, expected lowercase, uppercase or numeric", c.Schema.LevelFormat)
+		}
+		for _, key := range c.Schema.Order {
+			if !isSchemaKey(strings.ToLower(key)) {
+				invalid("Schema.Order: unknown key %q", key)
+			}
+		}
+	}
+	if c.CustomColors != nil {
+		type namedColor struct {
+			level string
+			color interface{}
+		}
+		custom := []namedColor{
+			{"Trace", c.CustomColors.Trace},
+			{"Debug", c.CustomColors.Debug},
+			{"Info", c.CustomColors.Info},
+			{"Warn", c.CustomColors.Warn},
+			{"Error", c.CustomColors.Error},
+		}
+		for name, color := range c.CustomColors.Levels {
+			if _, err := ParseLevel(name); err != nil {
+				invalid("CustomColors.Levels: unknown level %q", name)
+			}
+			custom = append(custom, namedColor{fmt.Sprintf("Levels[%q]", name), color})
+		}
+		for _, cc := range custom {
+			switch color := cc.color.(type) {
+			case nil, Color:
+			case *string:
+				if _, ok := colors[*color]; !ok {
+					invalid("CustomColors.%s: unknown color %q", cc.level, *color)
+				}
+			default:
+				invalid("CustomColors.%s: unsupported type %T, expected a Color or a *string", cc.level, cc.color)
+			}
+		}
+	}
+	for _, param := range c.SensitiveParams {
+		if _, err := parseRule(param); err != nil {
+			invalid("SensitiveParams: %v", strings.TrimPrefix(err.Error(), "noodlog: "))
+		}
+	}
+	for i, d := range c.Detectors {
+		if d.Name == "" || d.Pattern == nil {
+			invalid("Detectors[%d]: the name and the pattern are required", i)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("noodlog: invalid configs: %s", strings.Join(problems, "; "))
+}
+
+// isSchemaKey tells if the key is the default name of a built-in key
+func isSchemaKey(key string) bool {
+	switch key {
+	case levelKey, fileKey, functionKey, messageKey, timeKey, callerKey:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package noodlog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const yamlConfigs = `
+# noodlog configs
+logLevel: debug
+levelOverrides:
+  db: trace
+  "github.com/acme/*": warn
+format: logfmt
+output: stderr
+traceCaller: true
+colors: false
+customColors:
+  trace: cyan
+  warn: "#ff8800"   # hex colors must be quoted
+  error:
+    r: 255
+    g: 0
+    b: 0
+sensitiveParams:
+  - password
+  - 'items[*].token'
+schema:
+  messageKey: msg
+  order: [message, level]
+sinks:
+  - output: stdout
+    level: warn
+    format: console
+`
+
+// writeConfigsFile writes the content into a file of the test temporary directory
+func writeConfigsFile(t *testing.T, name, content string) string {
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadConfigsYAML(t *testing.T) {
+	configs, err := LoadConfigs(writeConfigsFile(t, "noodlog.yaml", yamlConfigs))
+	if err != nil {
+		t.Fatalf(errorFmt, "TestLoadConfigsYAML", nil, err)
+	}
+
+	if *configs.LogLevel != debugLabel || *configs.Format != logfmtFormat || !*configs.TraceCaller || *configs.Colors {
+		t.Errorf(errorFmt, "TestLoadConfigsYAML", "debug, logfmt, trace caller, no colors", configs)
+	}
+	if configs.LevelOverrides["db"] != traceLabel || configs.LevelOverrides["github.com/acme/*"] != warnLabel {
+		t.Errorf(errorFmt, "TestLoadConfigsYAML level overrides", "db and github.com/acme/*", configs.LevelOverrides)
+	}
+	if configs.LogWriter != os.Stderr {
+		t.Errorf(errorFmt, "TestLoadConfigsYAML output", os.Stderr.Name(), configs.LogWriter)
+	}
+	if strings.Join(configs.SensitiveParams, ",") != "password,items[*].token" {
+		t.Errorf(errorFmt, "TestLoadConfigsYAML sensitive params", "password,items[*].token", configs.SensitiveParams)
+	}
+	if len(configs.Sinks) != 1 || configs.Sinks[0].Writer() != os.Stdout || *configs.Sinks[0].Options().Format != consoleFormat {
+		t.Errorf(errorFmt, "TestLoadConfigsYAML sinks", "a console sink on stdout", configs.Sinks)
+	}
+	if configs.Schema.MessageKey != "msg" || strings.Join(configs.Schema.Order, ",") != "message,level" {
+		t.Errorf(errorFmt, "TestLoadConfigsYAML schema", "msg, [message level]", configs.Schema)
+	}
+
+	colorsMap := map[string]interface{}{
+		NewColor(Cyan).toCode():           detectColor(configs.CustomColors.Trace).toCode(),
+		NewColorRGB(255, 136, 0).toCode(): detectColor(configs.CustomColors.Warn).toCode(),
+		NewColorRGB(255, 0, 0).toCode():   detectColor(configs.CustomColors.Error).toCode(),
+	}
+	for expected, actual := range colorsMap {
+		if expected != actual {
+			t.Errorf(errorFmt, "TestLoadConfigsYAML colors", expected, actual)
+		}
+	}
+}
+
+func TestLoadConfigsJSON(t *testing.T) {
+	filename := writeConfigsFile(t, "noodlog.json", `{"logLevel":"warn","jsonPrettyPrint":true,"customColors":{"info":"green"}}`)
+	configs, err := LoadConfigs(filename)
+	if err != nil {
+		t.Fatalf(errorFmt, "TestLoadConfigsJSON", nil, err)
+	}
+	if *configs.LogLevel != warnLabel || !*configs.JSONPrettyPrint || *configs.CustomColors.Info.(*string) != greenColor {
+		t.Errorf(errorFmt, "TestLoadConfigsJSON", "warn, pretty print, green info", configs)
+	}
+
+	var embedded struct {
+		Logging Configs `json:"logging"`
+	}
+	if err := json.Unmarshal([]byte(`{"logging":{"format":"console"}}`), &embedded); err != nil || *embedded.Logging.Format != consoleFormat {
+		t.Errorf(errorFmt, "TestLoadConfigsJSON embedded", consoleFormat, err)
+	}
+}
+
+func TestLoadConfigsErrors(t *testing.T) {
+	testMap := map[string]string{
+		"unknown.json":  `{"logLevel":"info","verbose":true}`,
+		"type.json":     `{"colors":"yes"}`,
+		"color.json":    `{"customColors":{"warn":"#ff88"}}`,
+		"rgb.yaml":      "customColors:\n  warn: {r: 1, g: 2, b: 3}\n",
+		"invalid.json":  `{"logLevel":"verbose","format":"xml"}`,
+		"indent.yaml":   "logLevel: info\n  format: json\n",
+		"anchor.yaml":   "logLevel: &level info\n",
+		"documents.yml": "logLevel: info\n---\nlogLevel: warn\n",
+	}
+
+	for name, content := range testMap {
+		if _, err := LoadConfigs(writeConfigsFile(t, name, content)); err == nil {
+			t.Errorf(errorFmt, "TestLoadConfigsErrors "+name, "an error", nil)
+		}
+	}
+	if _, err := LoadConfigs(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf(errorFmt, "TestLoadConfigsErrors missing", "an error", nil)
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	testMap := map[string]string{
+		"":                                    `{}`,
+		"a: 1\nb: 1.5\nc: ~\nd: 'it''s'\n":    `{"a":1,"b":1.5,"c":null,"d":"it's"}`,
+		"list:\n- a\n- \"b # c\"\n":           `{"list":["a","b # c"]}`,
+		"list: [a, 'b, c', 3]\n":              `{"list":["a","b, c",3]}`,
+		"items:\n  - name: a\n    on: true\n": `{"items":[{"name":"a","on":true}]}`,
+		"url: http://example.com # comment":   `{"url":"http://example.com"}`,
+	}
+
+	for input, expected := range testMap {
+		actual, err := yamlToJSON([]byte(input))
+		if err != nil || string(actual) != expected {
+			t.Errorf(errorFmt, "TestYAMLToJSON "+input, expected, string(actual)+" "+errorString(err))
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Configs{LogLevel: LevelDebug, Format: FormatJSON, CustomColors: &CustomColors{Trace: Cyan, Warn: NewColorRGB(1, 2, 3)}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf(errorFmt, "TestValidate", nil, err)
+	}
+
+	invalid := Configs{
+		LogLevel:        pointerOfString("verbose"),
+		LevelOverrides:  map[string]string{"[db": "trace"},
+		Preset:          pointerOfString("aws"),
+		Schema:          &Schema{LevelFormat: "roman", Order: []string{"message", "thread"}},
+		CustomColors:    &CustomColors{Trace: "cyan", Info: pointerOfString("pink")},
+		SensitiveParams: []string{"items[x]"},
+	}
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatalf(errorFmt, "TestValidate", "an error", nil)
+	}
+	for _, expected := range []string{"LogLevel", "LevelOverrides", "Preset", "Schema.LevelFormat", "Schema.Order", "CustomColors.Trace", "CustomColors.Info", "SensitiveParams"} {
+		if !strings.Contains(err.Error(), expected+":") {
+			t.Errorf(errorFmt, "TestValidate", expected, err)
+		}
+	}
+}
+
+// errorString returns the message of err, empty if nil
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,143 @@
+package noodlog
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// defaultWatchInterval is the polling interval of a ConfigsWatcher created without interval
+const defaultWatchInterval = 5 * time.Second
+
+// ConfigsWatcher polls a configs file and applies its changes to a logger
+type ConfigsWatcher struct {
+	logger   *Logger
+	filename string
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// state of the last applied file, only accessed by the polling goroutine after the first load
+	modTime     time.Time
+	size        int64
+	output      *string
+	writer      *FileWriter
+	sinks       []fileSink
+	sinkWriters []*FileWriter
+}
+
+// WatchConfigs function loads a configs file (see LoadConfigs) into the logger, then polls the file with the given
+// interval, 5 seconds if not positive, and applies its changes. Every change is applied at once with SetConfigs, so that
+// a record is never written with a partial configuration, and is recorded by the logger. An invalid file is reported
+// and ignored, the logger keeping its previous configs. The settings removed from the file keep their last value.
+func WatchConfigs(l *Logger, filename string, interval time.Duration) (*ConfigsWatcher, error) {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	w := &ConfigsWatcher{
+		logger:   l,
+		filename: filename,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := w.reload(); err != nil {
+		return nil, err
+	}
+	go w.run(interval)
+	return w, nil
+}
+
+// Stop stops polling the file, the logger keeps the configs applied last
+func (w *ConfigsWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// run polls the file until the watcher is stopped
+func (w *ConfigsWatcher) run(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if !w.changed() {
+				continue
+			}
+			if err := w.reload(); err != nil {
+				w.logger.audit(errorLabel, "configs not reloaded", []Field{{"path", w.filename}, {"error", err.Error()}})
+			} else {
+				w.logger.audit(infoLabel, "configs reloaded", []Field{{"path", w.filename}})
+			}
+		}
+	}
+}
+
+// changed tells if the file was modified since it was applied last
+func (w *ConfigsWatcher) changed() bool {
+	info, err := os.Stat(w.filename)
+	if err != nil {
+		// reported by reload, unless the file was already missing
+		return !w.modTime.IsZero()
+	}
+	return !info.ModTime().Equal(w.modTime) || info.Size() != w.size
+}
+
+// reload reads, validates and applies the file. The file writers of the output and of the sinks are kept when they
+// don't change, and closed once replaced.
+func (w *ConfigsWatcher) reload() error {
+	info, err := os.Stat(w.filename)
+	if err != nil {
+		w.modTime, w.size = time.Time{}, 0
+		return fmt.Errorf("noodlog: %w", err)
+	}
+	// the file is considered applied even when invalid, so that it's reported only once
+	w.modTime, w.size = info.ModTime(), info.Size()
+
+	fc, err := loadFileConfigs(w.filename)
+	if err != nil {
+		return err
+	}
+	if fc.Output != nil && w.output != nil && *fc.Output == *w.output {
+		fc.Output = nil
+	}
+	if fc.Sinks != nil && w.sinks != nil && reflect.DeepEqual(fc.Sinks, w.sinks) {
+		fc.Sinks = nil
+	}
+	configs := fc.configs()
+	if err := configs.Validate(); err != nil {
+		return err
+	}
+	w.logger.SetConfigs(configs)
+
+	if configs.LogWriter != nil {
+		if w.writer != nil {
+			// the queued records are written before closing their file, which they would reopen otherwise
+			w.logger.Flush()
+			w.writer.Close()
+		}
+		w.writer, _ = configs.LogWriter.(*FileWriter)
+		w.output = fc.Output
+	}
+	if fc.Sinks != nil {
+		w.logger.Flush()
+		for _, writer := range w.sinkWriters {
+			writer.Close()
+		}
+		w.sinkWriters = nil
+		for _, sink := range configs.Sinks {
+			if writer, ok := sink.writer.(*FileWriter); ok {
+				w.sinkWriters = append(w.sinkWriters, writer)
+			}
+		}
+		w.sinks = fc.Sinks
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package noodlog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of the watcher and the reads of the test
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+// waitFor polls the condition until it's true or a second is elapsed
+func waitFor(condition func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+	return condition()
+}
+
+func TestWatchConfigs(t *testing.T) {
+	var b syncBuffer
+	filename := writeConfigsFile(t, "noodlog.yaml", "logLevel: warn\n")
+	l := NewLogger().LogWriter(&b)
+
+	w, err := WatchConfigs(l, filename, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf(errorFmt, "TestWatchConfigs", nil, err)
+	}
+	defer w.Stop()
+	if l.GetLevel() != WarnLevel {
+		t.Errorf(errorFmt, "TestWatchConfigs initial load", WarnLevel, l.GetLevel())
+	}
+
+	os.WriteFile(filename, []byte("logLevel: debug\nformat: logfmt\n"), 0644)
+	if !waitFor(func() bool { return l.GetLevel() == DebugLevel }) {
+		t.Fatalf(errorFmt, "TestWatchConfigs reload", DebugLevel, l.GetLevel())
+	}
+	if !waitFor(func() bool {
+		return strings.Contains(b.String(), `msg="configs reloaded" time=`) && strings.Contains(b.String(), "path="+filename)
+	}) {
+		t.Errorf(errorFmt, "TestWatchConfigs reload record", "configs reloaded", b.String())
+	}
+
+	os.WriteFile(filename, []byte("logLevel: verbose\n"), 0644)
+	if !waitFor(func() bool { return strings.Contains(b.String(), "configs not reloaded") }) {
+		t.Errorf(errorFmt, "TestWatchConfigs invalid", "configs not reloaded", b.String())
+	}
+	if l.GetLevel() != DebugLevel {
+		t.Errorf(errorFmt, "TestWatchConfigs invalid", DebugLevel, l.GetLevel())
+	}
+}
+
+func TestWatchConfigsAsync(t *testing.T) {
+	filename := writeConfigsFile(t, "noodlog.yaml", "async: true\n")
+	l := NewLogger().LogWriter(&syncBuffer{})
+	w, err := WatchConfigs(l, filename, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf(errorFmt, "TestWatchConfigsAsync", nil, err)
+	}
+	defer w.Stop()
+	defer l.Close()
+
+	child := l.With("requestId", "abc")
+	queue := l.async
+	for i, content := range []string{"async: true\nlogLevel: debug\n", "async: true\nlogLevel: warn\n"} {
+		os.WriteFile(filename, []byte(content), 0644)
+		if !waitFor(func() bool { return l.GetLevel() == []Level{DebugLevel, WarnLevel}[i] }) {
+			t.Fatalf(errorFmt, "TestWatchConfigsAsync reload", content, l.GetLevel())
+		}
+	}
+
+	l.mu.RLock()
+	same := l.async == queue
+	l.mu.RUnlock()
+	queue.closeMu.RLock()
+	closed := queue.closed
+	queue.closeMu.RUnlock()
+	if !same || closed || child.async != queue {
+		t.Errorf(errorFmt, "TestWatchConfigsAsync", "the derived logger still sharing an open queue", closed)
+	}
+}
+
+func TestWatchConfigsSinks(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "app.log")
+	sinks := "sinks:\n  - output: " + output + "\n    format: logfmt\n"
+	filename := writeConfigsFile(t, "noodlog.yaml", "logLevel: info\n"+sinks)
+	l := NewLogger()
+	w, err := WatchConfigs(l, filename, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf(errorFmt, "TestWatchConfigsSinks", nil, err)
+	}
+	defer w.Stop()
+
+	l.mu.RLock()
+	writer := l.sinks[0].writer
+	l.mu.RUnlock()
+	os.WriteFile(filename, []byte("logLevel: debug\n"+sinks), 0644)
+	if !waitFor(func() bool { return l.GetLevel() == DebugLevel }) {
+		t.Fatalf(errorFmt, "TestWatchConfigsSinks reload", DebugLevel, l.GetLevel())
+	}
+
+	l.mu.RLock()
+	kept := len(l.sinks) == 1 && l.sinks[0].writer == writer
+	l.mu.RUnlock()
+	if !kept {
+		t.Errorf(errorFmt, "TestWatchConfigsSinks", "the sink kept", l.Configs().Sinks)
+	}
+	l.Debug("hello")
+	writer.(*FileWriter).Close()
+	if content := readFile(t, output); !strings.Contains(content, "msg=hello") {
+		t.Errorf(errorFmt, "TestWatchConfigsSinks", "msg=hello", content)
+	}
+}
+
+func TestWatchConfigsInvalid(t *testing.T) {
+	filename := writeConfigsFile(t, "noodlog.json", `{"logLevel":"verbose"}`)
+	if w, err := WatchConfigs(NewLogger(), filename, 0); err == nil {
+		w.Stop()
+		t.Errorf(errorFmt, "TestWatchConfigsInvalid", "an error", nil)
+	}
+}
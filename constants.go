@@ -9,13 +9,13 @@ const (
 	panicLabel = "panic"
 	fatalLabel = "fatal"
 
-	traceLevel = 1
-	debugLevel = 2
-	infoLevel  = 3
-	warnLevel  = 4
-	errorLevel = 5
-	panicLevel = 6
-	fatalLevel = 7
+	traceLevel = 10
+	debugLevel = 20
+	infoLevel  = 30
+	warnLevel  = 40
+	errorLevel = 50
+	panicLevel = 60
+	fatalLevel = 70
 
 	defaultColor = "default"
 	redColor     = "red"
@@ -40,4 +40,16 @@ const (
 	backgroundBlue   = "44"
 	backgroundPurple = "45"
 	backgroundCyan   = "46"
+
+	obscuredValue = "**********"
+
+	jsonFormat    = "json"
+	logfmtFormat  = "logfmt"
+	consoleFormat = "console"
+
+	rfc3339TimeFormat     = "rfc3339"
+	rfc3339NanoTimeFormat = "rfc3339nano"
+	unixTimeFormat        = "unix"
+	unixMilliTimeFormat   = "unixmilli"
+	unixNanoTimeFormat    = "unixnano"
 )
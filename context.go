@@ -0,0 +1,92 @@
+package noodlog
+
+import "context"
+
+// contextKey is the key used to store a Logger into a context.Context
+type contextKey struct{}
+
+// ContextExtractor function retrieves from a context the fields to be added to a record, e.g. a request ID
+type ContextExtractor func(ctx context.Context) []Field
+
+// NewContext returns a copy of ctx carrying the given logger
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or a new default logger if ctx doesn't carry one
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok && l != nil {
+		return l
+	}
+	return NewLogger()
+}
+
+// ContextValue returns a ContextExtractor which emits the value stored in the context under key as the given field
+func ContextValue(key interface{}, field string) ContextExtractor {
+	return func(ctx context.Context) []Field {
+		if value := ctx.Value(key); value != nil {
+			return []Field{{Key: field, Value: value}}
+		}
+		return nil
+	}
+}
+
+// AddContextExtractor registers an extractor called by the context-aware logging methods, inherited by child loggers
+func (l *Logger) AddContextExtractor(extractor ContextExtractor) *Logger {
+	return l.update(func(s *settings) {
+		s.contextExtractors = append(s.contextExtractors[:len(s.contextExtractors):len(s.contextExtractors)], extractor)
+	})
+}
+
+// contextFields returns the fields extracted from ctx by the registered extractors; the caller must hold the read lock
+func (l *Logger) contextFields(ctx context.Context) []Field {
+	if ctx == nil || len(l.contextExtractors) == 0 {
+		return nil
+	}
+	var fields []Field
+	for _, extractor := range l.contextExtractors {
+		for _, f := range extractor(ctx) {
+			fields = setField(fields, f)
+		}
+	}
+	return fields
+}
+
+// TraceCtx function prints a log with trace log level and the fields extracted from ctx
+func (l *Logger) TraceCtx(ctx context.Context, message ...interface{}) {
+	l.printLog(ctx, traceLabel, message)
+}
+
+// DebugCtx function prints a log with debug log level and the fields extracted from ctx
+func (l *Logger) DebugCtx(ctx context.Context, message ...interface{}) {
+	l.printLog(ctx, debugLabel, message)
+}
+
+// InfoCtx function prints a log with info log level and the fields extracted from ctx
+func (l *Logger) InfoCtx(ctx context.Context, message ...interface{}) {
+	l.printLog(ctx, infoLabel, message)
+}
+
+// WarnCtx function prints a log with warn log level and the fields extracted from ctx
+func (l *Logger) WarnCtx(ctx context.Context, message ...interface{}) {
+	l.printLog(ctx, warnLabel, message)
+}
+
+// ErrorCtx function prints a log with error log level and the fields extracted from ctx
+func (l *Logger) ErrorCtx(ctx context.Context, message ...interface{}) {
+	l.printLog(ctx, errorLabel, message)
+}
+
+// PanicCtx function prints a log with panic log level and the fields extracted from ctx
+func (l *Logger) PanicCtx(ctx context.Context, message ...interface{}) {
+	l.mu.RLock()
+	logRecord := l.composeLog(ctx, panicLabel, l.composeMessage(message), nil)
+	l.mu.RUnlock()
+	panic(logRecord)
+}
+
+// FatalCtx function prints a log with fatal log level and the fields extracted from ctx
+func (l *Logger) FatalCtx(ctx context.Context, message ...interface{}) {
+	l.printLog(ctx, fatalLabel, message)
+	l.exit()
+}
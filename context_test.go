@@ -0,0 +1,82 @@
+package noodlog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+type ctxKey string
+
+func TestNewContextFromContext(t *testing.T) {
+	l := NewLogger().With("requestId", "a1b2")
+	ctx := NewContext(context.Background(), l)
+
+	if actual := FromContext(ctx); actual != l {
+		t.Errorf(errorFmt, "TestNewContextFromContext", l, actual)
+	}
+	if actual := FromContext(context.Background()); actual == nil || actual == l {
+		t.Errorf(errorFmt, "TestNewContextFromContext", "new default logger", actual)
+	}
+}
+
+func TestContextExtractors(t *testing.T) {
+	var b bytes.Buffer
+	tenant := func(ctx context.Context) []Field {
+		return []Field{{Key: "tenant", Value: ctx.Value(ctxKey("tenant"))}}
+	}
+	l := NewLogger().
+		SetConfigs(Configs{LogWriter: &b, ContextExtractors: []ContextExtractor{tenant}}).
+		AddContextExtractor(ContextValue(ctxKey("requestId"), "requestId")).
+		AddContextExtractor(ContextValue(ctxKey("missing"), "missing"))
+
+	ctx := context.WithValue(context.Background(), ctxKey("requestId"), "a1b2")
+	ctx = context.WithValue(ctx, ctxKey("tenant"), "gyoza")
+	ctx = NewContext(ctx, l.With("userId", 42))
+
+	FromContext(ctx).WarnCtx(ctx, "hello")
+
+	expected := `"message":"hello","time":"*","userId":42,"tenant":"gyoza","requestId":"a1b2"}`
+	if actual := b.String(); !Matches(actual, expected) || strings.Contains(actual, "missing") {
+		t.Errorf(errorFmt, "TestContextExtractors", expected, actual)
+	}
+	b.Reset()
+
+	l.Info("without context")
+	if actual := b.String(); strings.Contains(actual, "requestId") {
+		t.Errorf(errorFmt, "TestContextExtractors", "no context fields", actual)
+	}
+}
+
+func TestContextLoggingMethods(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).Level(traceLabel).EnableTraceCaller()
+	ctx := context.Background()
+
+	for label, logFunc := range map[string]func(context.Context, ...interface{}){
+		traceLabel: l.TraceCtx,
+		debugLabel: l.DebugCtx,
+		infoLabel:  l.InfoCtx,
+		warnLabel:  l.WarnCtx,
+		errorLabel: l.ErrorCtx,
+	} {
+		logFunc(ctx, "hello")
+		expected := `{"level":"` + label + `","file":"*context_test.go:*","function":"github.com/gyozatech/noodlog.TestContextLoggingMethods","message":"hello"`
+		if actual := b.String(); !Matches(actual, expected) {
+			t.Errorf(errorFmt, "TestContextLoggingMethods", expected, actual)
+		}
+		b.Reset()
+	}
+}
+
+func TestPanicCtx(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), `"requestId":"a1b2"`) {
+			t.Errorf(errorFmt, "TestPanicCtx", "panic with requestId", r)
+		}
+	}()
+
+	ctx := context.WithValue(context.Background(), ctxKey("requestId"), "a1b2")
+	NewLogger().AddContextExtractor(ContextValue(ctxKey("requestId"), "requestId")).PanicCtx(ctx, "bye")
+}
@@ -0,0 +1,184 @@
+package noodlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Entry struct represents a log record before it is encoded
+type Entry struct {
+	Level    string
+	Time     time.Time
+	File     string
+	Function string
+	Message  interface{}
+	Fields   []Field
+
+	// timeFormat is the representation of Time in the record, see FormattedTime
+	timeFormat string
+	// schema describes the built-in keys of the record, nil for the default ones
+	schema *Schema
+	// unfiltered entries are written by every output whatever its level
+	unfiltered bool
+}
+
+// Encoder interface converts an Entry into a log record, written by the logger followed by a newline
+type Encoder interface {
+	Encode(entry Entry) ([]byte, error)
+}
+
+// JSONEncoder encodes the records as JSON objects, optionally pretty printed
+type JSONEncoder struct {
+	PrettyPrint bool
+}
+
+// LogfmtEncoder encodes the records as logfmt lines: level=info msg="..." time="..."
+type LogfmtEncoder struct{}
+
+// ConsoleEncoder encodes the records as human-readable lines: 12:03:04 INFO  main.go:42 message key=value
+type ConsoleEncoder struct{}
+
+var encoders = map[string]Encoder{
+	jsonFormat:    JSONEncoder{},
+	logfmtFormat:  LogfmtEncoder{},
+	consoleFormat: ConsoleEncoder{},
+}
+
+// getEncoder returns the built-in encoder of a format, nil if the format doesn't exist
+func getEncoder(format string) Encoder {
+	return encoders[strings.ToLower(format)]
+}
+
+// Encode marshals the entry as a JSON record
+func (e JSONEncoder) Encode(entry Entry) ([]byte, error) {
+	if entry.schema != nil {
+		return e.encodeWithSchema(entry)
+	}
+	rec := record{
+		Level:   entry.Level,
+		Message: entry.Message,
+		Time:    entry.FormattedTime(),
+		Fields:  entry.Fields,
+	}
+	if entry.File != "" {
+		rec.File = &entry.File
+	}
+	if entry.Function != "" {
+		rec.Function = &entry.Function
+	}
+
+	if e.PrettyPrint {
+		return json.MarshalIndent(rec, "", "   ")
+	}
+	return json.Marshal(rec)
+}
+
+// Encode writes the entry as a sequence of key=value pairs
+func (e LogfmtEncoder) Encode(entry Entry) ([]byte, error) {
+	var b bytes.Buffer
+	if entry.schema != nil {
+		for _, f := range entry.builtinFields("msg") {
+			writeLogfmtPair(&b, f.Key, f.Value)
+		}
+		writeLogfmtFields(&b, entry)
+		return b.Bytes(), nil
+	}
+	writeLogfmtPair(&b, "level", entry.Level)
+	if entry.File != "" {
+		writeLogfmtPair(&b, "file", entry.File)
+	}
+	if entry.Function != "" {
+		writeLogfmtPair(&b, "function", entry.Function)
+	}
+	writeLogfmtPair(&b, "msg", entry.Message)
+	writeLogfmtPair(&b, "time", entry.FormattedTime())
+	writeLogfmtFields(&b, entry)
+	return b.Bytes(), nil
+}
+
+// Encode writes the entry as a line meant to be read by humans
+func (e ConsoleEncoder) Encode(entry Entry) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %-5s", entry.Time.Format("15:04:05"), strings.ToUpper(entry.Level))
+	if entry.File != "" {
+		b.WriteString(" " + filepath.Base(entry.File))
+	}
+	if msg := toText(entry.Message); msg != "" {
+		b.WriteString(" " + msg)
+	}
+	writeLogfmtFields(&b, entry)
+	return b.Bytes(), nil
+}
+
+// FormattedTime returns the time of the entry in the format configured on the logger:
+// a string for RFC3339, RFC3339Nano and the custom layouts, a number for the Unix formats
+func (e Entry) FormattedTime() interface{} {
+	switch strings.ToLower(e.timeFormat) {
+	case "":
+		// the time without its monotonic clock reading
+		return e.Time.Round(0).String()
+	case rfc3339TimeFormat:
+		return e.Time.Format(time.RFC3339)
+	case rfc3339NanoTimeFormat:
+		return e.Time.Format(time.RFC3339Nano)
+	case unixTimeFormat:
+		return e.Time.Unix()
+	case unixMilliTimeFormat:
+		return e.Time.UnixNano() / int64(time.Millisecond)
+	case unixNanoTimeFormat:
+		return e.Time.UnixNano()
+	}
+	return e.Time.Format(e.timeFormat)
+}
+
+func writeLogfmtFields(b *bytes.Buffer, entry Entry) {
+	for _, f := range entry.Fields {
+		if !entry.isReservedKey(f.Key) {
+			writeLogfmtPair(b, f.Key, f.Value)
+		}
+	}
+}
+
+func writeLogfmtPair(b *bytes.Buffer, key string, value interface{}) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	b.WriteString(quoteLogfmt(toText(value)))
+}
+
+// toText returns the text of a value: strings are kept as they are, other values are marshalled as JSON
+func toText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	jsn, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(jsn)
+}
+
+// quoteLogfmt quotes a value if it is empty or contains spaces, quotes, equal signs or control characters
+func quoteLogfmt(value string) string {
+	if value == "" || strings.IndexFunc(value, needsQuoting) != -1 {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
+func needsQuoting(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == 0x7f
+}
@@ -0,0 +1,125 @@
+package noodlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testEntry = Entry{
+	Level:    infoLabel,
+	Time:     time.Date(2021, 3, 4, 12, 3, 4, 0, time.UTC),
+	File:     "/home/gyoza/main.go:42",
+	Function: "main.main",
+	Message:  "user logged in",
+	Fields:   []Field{{"requestId", "a1b2"}, {"attempts", 3}},
+}
+
+func TestJSONEncoder(t *testing.T) {
+	expected := `{"level":"info","file":"/home/gyoza/main.go:42","function":"main.main","message":"user logged in","time":"2021-03-04 12:03:04 +0000 UTC","requestId":"a1b2","attempts":3}`
+	actual, err := JSONEncoder{}.Encode(testEntry)
+	if err != nil || string(actual) != expected {
+		t.Errorf(errorFmt, "TestJSONEncoder", expected, string(actual))
+	}
+
+	pretty, _ := JSONEncoder{PrettyPrint: true}.Encode(testEntry)
+	if !strings.Contains(string(pretty), "\n   \"level\": \"info\",\n") {
+		t.Errorf(errorFmt, "TestJSONEncoder", "pretty printed record", string(pretty))
+	}
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	entry := testEntry
+	entry.Fields = append(entry.Fields, Field{"user", map[string]string{"name": "gyoza"}}, Field{"empty", ""})
+
+	expected := `level=info file=/home/gyoza/main.go:42 function=main.main msg="user logged in" time="2021-03-04 12:03:04 +0000 UTC" requestId=a1b2 attempts=3 user="{\"name\":\"gyoza\"}" empty=""`
+	actual, err := LogfmtEncoder{}.Encode(entry)
+	if err != nil || string(actual) != expected {
+		t.Errorf(errorFmt, "TestLogfmtEncoder", expected, string(actual))
+	}
+}
+
+func TestConsoleEncoder(t *testing.T) {
+	expected := `12:03:04 INFO  main.go:42 user logged in requestId=a1b2 attempts=3`
+	actual, err := ConsoleEncoder{}.Encode(testEntry)
+	if err != nil || string(actual) != expected {
+		t.Errorf(errorFmt, "TestConsoleEncoder", expected, string(actual))
+	}
+}
+
+func TestLoggerFormat(t *testing.T) {
+	testMap := map[string]string{
+		jsonFormat:    `{"level":"info","message":"hello","time":"*","key":"value"}`,
+		"JSON":        `{"level":"info","message":"hello","time":"*","key":"value"}`,
+		"unknown":     `{"level":"info","message":"hello","time":"*","key":"value"}`,
+		logfmtFormat:  `level=info msg=hello time="*" key=value`,
+		consoleFormat: `*:*:* INFO  hello key=value`,
+	}
+
+	var b bytes.Buffer
+	for format, expected := range testMap {
+		l := NewLogger().LogWriter(&b).Format(format).With("key", "value")
+		l.Info("hello")
+		if actual := b.String(); !Matches(actual, expected) {
+			t.Errorf(errorFmt, "TestLoggerFormat "+format, expected, actual)
+		}
+		b.Reset()
+	}
+}
+
+type upperEncoder struct{}
+
+func (e upperEncoder) Encode(entry Entry) ([]byte, error) {
+	return []byte(strings.ToUpper(toText(entry.Message))), nil
+}
+
+func TestLoggerCustomEncoder(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().SetConfigs(Configs{LogWriter: &b, Encoder: upperEncoder{}})
+
+	l.Info("hello")
+	if expected, actual := "HELLO\n", b.String(); actual != expected {
+		t.Errorf(errorFmt, "TestLoggerCustomEncoder", expected, actual)
+	}
+}
+
+func TestEntryFormattedTime(t *testing.T) {
+	entry := testEntry
+	entry.Time = time.Date(2021, 3, 4, 12, 3, 4, 5000000, time.FixedZone("CET", 3600))
+	testMap := map[string]interface{}{
+		"":                 "2021-03-04 12:03:04.005 +0100 CET",
+		"RFC3339":          "2021-03-04T12:03:04+01:00",
+		"rfc3339nano":      "2021-03-04T12:03:04.005+01:00",
+		"Unix":             int64(1614855784),
+		"UnixMilli":        int64(1614855784005),
+		"UnixNano":         int64(1614855784005000000),
+		"2006-01-02 15:04": "2021-03-04 12:03",
+	}
+
+	for format, expected := range testMap {
+		entry.timeFormat = format
+		if actual := entry.FormattedTime(); actual != expected {
+			t.Errorf(errorFmt, "TestEntryFormattedTime "+format, expected, actual)
+		}
+	}
+}
+
+func TestLoggerTimeSettings(t *testing.T) {
+	var b bytes.Buffer
+	clock := func() time.Time { return time.Date(2021, 3, 4, 12, 3, 4, 0, time.FixedZone("CET", 3600)) }
+	l := NewLogger().SetConfigs(Configs{LogWriter: &b, TimeFormat: TimeFormatRFC3339, UTC: Enable, Clock: clock})
+
+	l.Info("hello")
+	expected := `{"level":"info","message":"hello","time":"2021-03-04T11:03:04Z"}` + "\n"
+	if actual := b.String(); actual != expected {
+		t.Errorf(errorFmt, "TestLoggerTimeSettings", expected, actual)
+	}
+	b.Reset()
+
+	l.TimeFormat("UnixMilli").DisableUTC().Format(logfmtFormat).Info("hello")
+	expected = "level=info msg=hello time=1614855784000\n"
+	if actual := b.String(); actual != expected {
+		t.Errorf(errorFmt, "TestLoggerTimeSettings", expected, actual)
+	}
+}
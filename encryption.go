@@ -0,0 +1,107 @@
+package noodlog
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// encryptedPrefix starts the values replaced by an encryption redaction: enc:<key ID>:<base64 nonce and ciphertext>
+const encryptedPrefix = "enc:"
+
+// keyIDPattern lists the characters allowed in a key ID, so that the encrypted values are recognized in the records
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// encryptedValuePattern matches the JSON strings containing an encrypted value
+var encryptedValuePattern = regexp.MustCompile(`"enc:([A-Za-z0-9._-]+):([A-Za-z0-9+/]+=*)"`)
+
+// encryptRedaction replaces the value with its AES-GCM ciphertext
+type encryptRedaction struct {
+	keyID string
+	aead  cipher.AEAD
+}
+
+// Encrypt returns a Redaction replacing the value with its AES-GCM encryption, tagged with keyID, so that
+// whoever owns the key can recover it with DecryptRecord. The key must be 16, 24 or 32 bytes long.
+func Encrypt(keyID string, key []byte) (Redaction, error) {
+	if !keyIDPattern.MatchString(keyID) {
+		return nil, fmt.Errorf("noodlog: invalid key ID %q, only letters, digits, '.', '_' and '-' are allowed", keyID)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return encryptRedaction{keyID: keyID, aead: aead}, nil
+}
+
+// Redact replaces the value with enc:<key ID>:<base64 of nonce and ciphertext of its JSON representation>
+func (r encryptRedaction) Redact(value interface{}) (interface{}, bool) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return obscuredValue, true
+	}
+	nonce := make([]byte, r.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return obscuredValue, true
+	}
+	sealed := r.aead.Seal(nonce, nonce, plaintext, []byte(r.keyID))
+	return encryptedPrefix + r.keyID + ":" + base64.StdEncoding.EncodeToString(sealed), true
+}
+
+// DecryptRecord function restores the values encrypted in a JSON record, given the keys indexed by key ID.
+// The values encrypted with unknown key IDs are left untouched.
+func DecryptRecord(record []byte, keys map[string][]byte) ([]byte, error) {
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for keyID, key := range keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("noodlog: key %q: %w", keyID, err)
+		}
+		aeads[keyID] = aead
+	}
+
+	var decryptErr error
+	decrypted := encryptedValuePattern.ReplaceAllFunc(record, func(match []byte) []byte {
+		groups := encryptedValuePattern.FindSubmatch(match)
+		aead, ok := aeads[string(groups[1])]
+		if !ok || decryptErr != nil {
+			return match
+		}
+		plaintext, err := decryptValue(aead, string(groups[1]), string(groups[2]))
+		if err != nil {
+			decryptErr = fmt.Errorf("noodlog: unable to decrypt a value encrypted with key %q: %w", groups[1], err)
+			return match
+		}
+		return plaintext
+	})
+	if decryptErr != nil {
+		return nil, decryptErr
+	}
+	return decrypted, nil
+}
+
+// decryptValue returns the JSON representation of an encrypted value
+func decryptValue(aead cipher.AEAD, keyID, encoded string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(keyID))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("noodlog: invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
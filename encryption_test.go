@@ -0,0 +1,51 @@
+package noodlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestEncryptInvalidArguments(t *testing.T) {
+	if _, err := Encrypt("key 1", testKey); err == nil {
+		t.Errorf(errorFmt, "TestEncryptInvalidArguments", "invalid key ID error", nil)
+	}
+	if _, err := Encrypt("k1", []byte("short")); err == nil {
+		t.Errorf(errorFmt, "TestEncryptInvalidArguments", "invalid key error", nil)
+	}
+}
+
+func TestEncryptAndDecryptRecord(t *testing.T) {
+	encrypt, err := Encrypt("k1", testKey)
+	if err != nil {
+		t.Fatalf(errorFmt, "TestEncryptAndDecryptRecord", nil, err)
+	}
+
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).
+		EnableObscureSensitiveData([]string{"cardNumber", "pin", "password"}).
+		SetDefaultRedaction(encrypt).
+		SetRedaction("password", Mask())
+
+	l.Info(`{"cardNumber": "4111111111111111", "pin": 1234, "password": "p"}`)
+	record := b.Bytes()
+	if strings.Contains(string(record), "4111111111111111") || !strings.Contains(string(record), `"cardNumber":"enc:k1:`) {
+		t.Fatalf(errorFmt, "TestEncryptAndDecryptRecord", "encrypted card number", string(record))
+	}
+
+	if actual, err := DecryptRecord(record, map[string][]byte{"k2": testKey}); err != nil || !bytes.Equal(actual, record) {
+		t.Errorf(errorFmt, "TestEncryptAndDecryptRecord", "record untouched by unknown keys", string(actual))
+	}
+
+	expected := `{"level":"info","message":{"cardNumber":"4111111111111111","password":"**********","pin":1234},"time":"*"}`
+	actual, err := DecryptRecord(record, map[string][]byte{"k1": testKey})
+	if err != nil || !Matches(string(actual), expected) {
+		t.Errorf(errorFmt, "TestEncryptAndDecryptRecord", expected, string(actual))
+	}
+
+	if _, err := DecryptRecord(record, map[string][]byte{"k1": []byte("fedcba9876543210fedcba9876543210")}); err == nil {
+		t.Errorf(errorFmt, "TestEncryptAndDecryptRecord", "wrong key error", nil)
+	}
+}
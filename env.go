@@ -0,0 +1,136 @@
+package noodlog
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultEnvPrefix is the prefix of the environment variables read by FromEnv when no prefix is given
+const defaultEnvPrefix = "NOODLOG"
+
+// FromEnv function builds the Configs from the environment variables with the given prefix, "NOODLOG" if empty:
+//   - NOODLOG_LEVEL: trace, debug, info, warn, error, panic, fatal or a registered level
+//   - NOODLOG_LEVEL_OVERRIDES: pattern=level pairs, e.g. "db=trace,http=warn" (see SetLevelOverrides)
+//   - NOODLOG_FORMAT: json, logfmt or console
+//   - NOODLOG_PRESET: gcp, ecs or datadog
+//   - NOODLOG_OUTPUT: stdout, stderr or the path of a file, opened by FromEnv as a *FileWriter which the caller closes
+//   - NOODLOG_TIME_FORMAT: rfc3339, rfc3339nano, unix, unixmilli, unixnano or a time layout
+//   - NOODLOG_PRETTY, NOODLOG_COLORS, NOODLOG_TRACE_CALLER, NOODLOG_UTC: booleans, e.g. true or false
+//   - NOODLOG_SENSITIVE_PARAMS: comma separated params obscured in the records, which enables the obscuring
+//
+// The unset variables are left nil in the Configs, so that they don't override the settings of the logger.
+// The invalid values are reported together in the error, while the valid ones are still returned.
+func FromEnv(prefix string) (Configs, error) {
+	prefix = strings.TrimSuffix(prefix, "_")
+	if prefix == "" {
+		prefix = defaultEnvPrefix
+	}
+	env := envReader{prefix: prefix + "_"}
+	configs := Configs{}
+
+	if level, ok := env.lookup("LEVEL"); ok {
+		if _, err := ParseLevel(level); err != nil {
+			env.invalid("LEVEL", level, "unknown level")
+		} else {
+			configs.LogLevel = pointerOfString(level)
+		}
+	}
+	if spec, ok := env.lookup("LEVEL_OVERRIDES"); ok {
+		if overrides, err := ParseLevelOverrides(spec); err != nil {
+			env.invalid("LEVEL_OVERRIDES", spec, strings.TrimPrefix(err.Error(), "noodlog: "))
+		} else {
+			configs.LevelOverrides = overrides
+		}
+	}
+	if format, ok := env.lookup("FORMAT"); ok {
+		if getEncoder(format) == nil {
+			env.invalid("FORMAT", format, "expected json, logfmt or console")
+		} else {
+			configs.Format = pointerOfString(strings.ToLower(format))
+		}
+	}
+	if preset, ok := env.lookup("PRESET"); ok {
+		if _, exists := presets[strings.ToLower(preset)]; !exists {
+			env.invalid("PRESET", preset, "expected gcp, ecs or datadog")
+		} else {
+			configs.Preset = pointerOfString(strings.ToLower(preset))
+		}
+	}
+	if output, ok := env.lookup("OUTPUT"); ok {
+		switch strings.ToLower(output) {
+		case "stdout":
+			configs.LogWriter = os.Stdout
+		case "stderr":
+			configs.LogWriter = os.Stderr
+		default:
+			// the file is opened now, so that an unwritable path is reported with the other invalid values
+			w := NewFileWriter(output, FileWriterOptions{})
+			if err := w.open(); err != nil {
+				w.close()
+				env.invalid("OUTPUT", output, strings.TrimPrefix(err.Error(), "noodlog: "))
+			} else {
+				configs.LogWriter = w
+			}
+		}
+	}
+	if timeFormat, ok := env.lookup("TIME_FORMAT"); ok {
+		configs.TimeFormat = pointerOfString(timeFormat)
+	}
+	configs.JSONPrettyPrint = env.boolean("PRETTY")
+	configs.Colors = env.boolean("COLORS")
+	configs.TraceCaller = env.boolean("TRACE_CALLER")
+	configs.UTC = env.boolean("UTC")
+	if params, ok := env.lookup("SENSITIVE_PARAMS"); ok {
+		for _, param := range strings.Split(params, ",") {
+			if param = strings.TrimSpace(param); param != "" {
+				configs.SensitiveParams = append(configs.SensitiveParams, param)
+			}
+		}
+		if len(configs.SensitiveParams) > 0 {
+			configs.ObscureSensitiveData = pointerOfBool(true)
+		}
+	}
+
+	return configs, env.err()
+}
+
+// envReader reads the variables with a prefix and collects the invalid values
+type envReader struct {
+	prefix string
+	errors []string
+}
+
+// lookup returns the trimmed value of a variable, false if it's unset or empty
+func (r *envReader) lookup(name string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(r.prefix + name))
+	return value, value != ""
+}
+
+// boolean returns the value of a boolean variable, nil if it's unset or invalid
+func (r *envReader) boolean(name string) *bool {
+	value, ok := r.lookup(name)
+	if !ok {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.invalid(name, value, "expected true or false")
+		return nil
+	}
+	return pointerOfBool(b)
+}
+
+// invalid records an invalid value
+func (r *envReader) invalid(name, value, reason string) {
+	r.errors = append(r.errors, fmt.Sprintf("%s%s=%q: %s", r.prefix, name, value, reason))
+}
+
+// err returns an error listing the invalid values, nil if there are none
+func (r *envReader) err() error {
+	if len(r.errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("noodlog: invalid environment: %s", strings.Join(r.errors, "; "))
+}
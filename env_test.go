@@ -0,0 +1,129 @@
+package noodlog
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// setEnv sets the environment variables for the duration of the test
+func setEnv(t *testing.T, vars map[string]string) {
+	for name, value := range vars {
+		os.Setenv(name, value)
+	}
+	t.Cleanup(func() {
+		for name := range vars {
+			os.Unsetenv(name)
+		}
+	})
+}
+
+func TestFromEnv(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	setEnv(t, map[string]string{
+		"APP_LEVEL":            "debug",
+		"APP_LEVEL_OVERRIDES":  "db=trace",
+		"APP_FORMAT":           "Logfmt",
+		"APP_OUTPUT":           file,
+		"APP_PRETTY":           "false",
+		"APP_TRACE_CALLER":     "1",
+		"APP_SENSITIVE_PARAMS": "password, token",
+	})
+
+	configs, err := FromEnv("APP_")
+	if err != nil {
+		t.Fatalf(errorFmt, "TestFromEnv", nil, err)
+	}
+	if configs.LogLevel == nil || *configs.LogLevel != debugLabel {
+		t.Errorf(errorFmt, "TestFromEnv level", debugLabel, configs.LogLevel)
+	}
+	if configs.LevelOverrides["db"] != traceLabel {
+		t.Errorf(errorFmt, "TestFromEnv level overrides", "map[db:trace]", configs.LevelOverrides)
+	}
+	if configs.Format == nil || *configs.Format != logfmtFormat {
+		t.Errorf(errorFmt, "TestFromEnv format", logfmtFormat, configs.Format)
+	}
+	if configs.JSONPrettyPrint == nil || *configs.JSONPrettyPrint || configs.TraceCaller == nil || !*configs.TraceCaller {
+		t.Errorf(errorFmt, "TestFromEnv booleans", "pretty false, trace caller true", configs)
+	}
+	if configs.Colors != nil || configs.Preset != nil || configs.TimeFormat != nil {
+		t.Errorf(errorFmt, "TestFromEnv unset", "nil", configs)
+	}
+	if len(configs.SensitiveParams) != 2 || configs.ObscureSensitiveData == nil || !*configs.ObscureSensitiveData {
+		t.Errorf(errorFmt, "TestFromEnv sensitive params", "[password token]", configs.SensitiveParams)
+	}
+
+	l := NewLogger().SetConfigs(configs)
+	l.Info("password=Sup3rS3cr3t")
+	if w, ok := configs.LogWriter.(*FileWriter); ok {
+		w.Close()
+	}
+	content, _ := os.ReadFile(file)
+	if expected := "level=info"; !strings.Contains(string(content), expected) {
+		t.Errorf(errorFmt, "TestFromEnv output", expected, string(content))
+	}
+}
+
+func TestFromEnvOutput(t *testing.T) {
+	testMap := map[string]*os.File{
+		"stdout": os.Stdout,
+		"STDERR": os.Stderr,
+	}
+
+	for output, expected := range testMap {
+		setEnv(t, map[string]string{"NOODLOG_OUTPUT": output})
+		configs, err := FromEnv("")
+		if err != nil || configs.LogWriter != expected {
+			t.Errorf(errorFmt, "TestFromEnvOutput "+output, expected.Name(), configs.LogWriter)
+		}
+	}
+}
+
+func TestFromEnvOutputUnwritable(t *testing.T) {
+	parent := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(parent, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(parent, "app.log")
+	setEnv(t, map[string]string{"NOODLOG_OUTPUT": output})
+
+	configs, err := FromEnv("")
+	if err == nil || !strings.Contains(err.Error(), "NOODLOG_OUTPUT="+strconv.Quote(output)) {
+		t.Errorf(errorFmt, "TestFromEnvOutputUnwritable", "NOODLOG_OUTPUT error", err)
+	}
+	if configs.LogWriter != nil {
+		t.Errorf(errorFmt, "TestFromEnvOutputUnwritable", nil, configs.LogWriter)
+	}
+}
+
+func TestFromEnvSensitiveParams(t *testing.T) {
+	setEnv(t, map[string]string{"NOODLOG_SENSITIVE_PARAMS": "password"})
+	configs, _ := FromEnv("")
+	if configs.ObscureSensitiveData == Enable {
+		t.Errorf(errorFmt, "TestFromEnvSensitiveParams", "a pointer not shared with Enable", configs.ObscureSensitiveData)
+	}
+}
+
+func TestFromEnvInvalid(t *testing.T) {
+	setEnv(t, map[string]string{
+		"NOODLOG_LEVEL":  "verbose",
+		"NOODLOG_FORMAT": "xml",
+		"NOODLOG_COLORS": "sometimes",
+		"NOODLOG_UTC":    "true",
+	})
+
+	configs, err := FromEnv("NOODLOG")
+	if err == nil {
+		t.Fatalf(errorFmt, "TestFromEnvInvalid", "an error", nil)
+	}
+	for _, expected := range []string{`NOODLOG_LEVEL="verbose"`, `NOODLOG_FORMAT="xml"`, `NOODLOG_COLORS="sometimes"`} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf(errorFmt, "TestFromEnvInvalid", expected, err)
+		}
+	}
+	if configs.LogLevel != nil || configs.Format != nil || configs.Colors != nil || configs.UTC == nil || !*configs.UTC {
+		t.Errorf(errorFmt, "TestFromEnvInvalid", "only the valid values", configs)
+	}
+}
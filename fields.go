@@ -0,0 +1,135 @@
+package noodlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// badKey is the key used for a value passed to With without its key
+const badKey = "!BADKEY"
+
+// Field represents a key-value pair emitted as a top-level key of every log record
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// builtinKeys contains the record keys which cannot be overridden by fields
+var builtinKeys = map[string]bool{
+	"level":    true,
+	"file":     true,
+	"function": true,
+	"message":  true,
+	"time":     true,
+}
+
+// With returns a child logger which emits the given key-value pairs in every record.
+// Keys and values alternate: With("requestId", id, "userId", user).
+// Fields already defined by the parent logger are overridden.
+func (l *Logger) With(keysAndValues ...interface{}) *Logger {
+	return l.withFields(fieldsFromKeyValues(keysAndValues))
+}
+
+// WithFields returns a child logger which emits the given map entries in every record.
+// Fields already defined by the parent logger are overridden.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	return l.withFields(fieldsFromMap(fields))
+}
+
+func (l *Logger) withFields(fields []Field) *Logger {
+	child := l.clone()
+	child.fields = mergeFields(child.fields, fields)
+	return child
+}
+
+// mergeFields returns a new slice with the fields of parent overridden or extended by the ones of child
+func mergeFields(parent []Field, child []Field) []Field {
+	merged := make([]Field, len(parent), len(parent)+len(child))
+	copy(merged, parent)
+	for _, f := range child {
+		merged = setField(merged, f)
+	}
+	return merged
+}
+
+// setField overrides the value of a field already present in fields or appends it
+func setField(fields []Field, field Field) []Field {
+	for i := range fields {
+		if fields[i].Key == field.Key {
+			fields[i].Value = field.Value
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+// fieldsFromKeyValues converts an alternating list of keys and values into fields.
+// Keys which are not strings are stringified, a trailing value without key is stored under badKey.
+func fieldsFromKeyValues(keysAndValues []interface{}) []Field {
+	fields := make([]Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i == len(keysAndValues)-1 {
+			fields = setField(fields, Field{Key: badKey, Value: keysAndValues[i]})
+			break
+		}
+		fields = setField(fields, Field{Key: keyToString(keysAndValues[i]), Value: keysAndValues[i+1]})
+	}
+	return fields
+}
+
+// fieldsFromMap converts a map into fields sorted by key, so that the output is deterministic
+func fieldsFromMap(m map[string]interface{}) []Field {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, Field{Key: k, Value: m[k]})
+	}
+	return fields
+}
+
+func keyToString(key interface{}) string {
+	if k, ok := key.(string); ok {
+		return k
+	}
+	return fmt.Sprint(key)
+}
+
+// MarshalJSON marshals the record keys followed by the fields in their insertion order
+func (r record) MarshalJSON() ([]byte, error) {
+	type plainRecord record
+	jsn, err := json.Marshal(plainRecord(r))
+	if err != nil || len(r.Fields) == 0 {
+		return jsn, err
+	}
+
+	var b bytes.Buffer
+	b.Write(jsn[:len(jsn)-1])
+	for _, f := range r.Fields {
+		if builtinKeys[f.Key] {
+			continue
+		}
+		b.WriteByte(',')
+		writeJSONField(&b, f)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// writeJSONField writes a field as a JSON key-value pair, stringifying the values which can't be marshalled
+func writeJSONField(b *bytes.Buffer, f Field) {
+	key, _ := json.Marshal(f.Key)
+	value, err := json.Marshal(f.Value)
+	if err != nil {
+		value, _ = json.Marshal(fmt.Sprint(f.Value))
+	}
+	b.Write(key)
+	b.WriteByte(':')
+	b.Write(value)
+}
@@ -0,0 +1,69 @@
+package noodlog
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFieldsFromKeyValues(t *testing.T) {
+	testMap := map[string]struct {
+		input    []interface{}
+		expected []Field
+	}{
+		"empty":    {[]interface{}{}, []Field{}},
+		"pairs":    {[]interface{}{"a", 1, "b", "two"}, []Field{{"a", 1}, {"b", "two"}}},
+		"odd":      {[]interface{}{"a", 1, "dangling"}, []Field{{"a", 1}, {badKey, "dangling"}}},
+		"non-str":  {[]interface{}{42, true}, []Field{{"42", true}}},
+		"override": {[]interface{}{"a", 1, "a", 2}, []Field{{"a", 2}}},
+	}
+
+	for name, test := range testMap {
+		if actual := fieldsFromKeyValues(test.input); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf(errorFmt, "TestFieldsFromKeyValues "+name, test.expected, actual)
+		}
+	}
+}
+
+func TestFieldsFromMap(t *testing.T) {
+	expected := []Field{{"a", 1}, {"b", 2}, {"c", 3}}
+	actual := fieldsFromMap(map[string]interface{}{"c": 3, "a": 1, "b": 2})
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf(errorFmt, "TestFieldsFromMap", expected, actual)
+	}
+}
+
+func TestWithInheritsAndOverrides(t *testing.T) {
+	parent := NewLogger().With("service", "noodlog", "requestId", "abc")
+	child := parent.WithFields(map[string]interface{}{"requestId": "def", "userId": 7})
+
+	expectedParent := []Field{{"service", "noodlog"}, {"requestId", "abc"}}
+	if !reflect.DeepEqual(parent.fields, expectedParent) {
+		t.Errorf(errorFmt, "TestWithInheritsAndOverrides", expectedParent, parent.fields)
+	}
+	expectedChild := []Field{{"service", "noodlog"}, {"requestId", "def"}, {"userId", 7}}
+	if !reflect.DeepEqual(child.fields, expectedChild) {
+		t.Errorf(errorFmt, "TestWithInheritsAndOverrides", expectedChild, child.fields)
+	}
+}
+
+func TestWithLogging(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().
+		LogWriter(&b).
+		EnableObscureSensitiveData([]string{"password"}).
+		With("requestId", "abc", "password", "Sup3rS3cr3t", "err", errors.New("boom"), "level", "ignored")
+
+	l.Info("hello")
+
+	actual := b.String()
+	expected := `"message":"hello","time":"*","requestId":"abc","password":"**********","err":"boom"}`
+	if !Matches(actual, expected) {
+		t.Errorf(errorFmt, "TestWithLogging", expected, actual)
+	}
+	if strings.Contains(actual, "ignored") {
+		t.Errorf(errorFmt, "TestWithLogging", "builtin key not overridden", actual)
+	}
+}
@@ -0,0 +1,344 @@
+package noodlog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation tells when a FileWriter rotates its file regardless of its size
+type Rotation int
+
+const (
+	// NoTimeRotation rotates the file only by size
+	NoTimeRotation Rotation = iota
+	// DailyRotation rotates the file at the first write of every day
+	DailyRotation
+	// HourlyRotation rotates the file at the first write of every hour
+	HourlyRotation
+)
+
+// backupTimeFormat is the layout of the rotation time included in the name of the backups
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// FileWriterOptions struct contains the rotation and retention settings of a FileWriter
+type FileWriterOptions struct {
+	// MaxSize is the size in bytes after which the file is rotated, 0 disables the size-based rotation
+	MaxSize int64
+	// Rotation enables the time-based rotation
+	Rotation Rotation
+	// MaxBackups is the number of rotated files to keep, 0 keeps all of them
+	MaxBackups int
+	// MaxAge is the time after which the rotated files are removed, 0 keeps all of them
+	MaxAge time.Duration
+	// Compress enables the gzip compression of the rotated files
+	Compress bool
+	// Symlink is the path of a symbolic link kept pointing to the file being written
+	Symlink string
+}
+
+// FileWriter is an io.WriteCloser writing to a file rotated by size and time.
+// The file being written keeps its name, while the rotated ones are renamed
+// name-2006-01-02T15-04-05.000.ext with the time of the rotation, followed by a sequence number,
+// e.g. name-2006-01-02T15-04-05.000.1.ext, when several rotations happen in the same millisecond.
+// It is safe for concurrent use.
+type FileWriter struct {
+	mu       sync.Mutex
+	filename string
+	options  FileWriterOptions
+	now      func() time.Time
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// cleanups tracks the compression and the retention of the rotated files, done in background
+	// one at a time so that the writes don't wait for them
+	cleanups  sync.WaitGroup
+	cleanupMu sync.Mutex
+}
+
+// NewFileWriter func is the constructor of a FileWriter. The file is opened, or created, at the first write
+func NewFileWriter(filename string, options FileWriterOptions) *FileWriter {
+	return &FileWriter{
+		filename: filename,
+		options:  options,
+		now:      time.Now,
+	}
+}
+
+// Write writes p to the file, rotating it before when needed
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.mustRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate forces the rotation of the file
+func (w *FileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotate()
+}
+
+// Close closes the file, which is reopened by the following writes,
+// and waits for the compression and the removal of the rotated files
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	err := w.close()
+	w.mu.Unlock()
+
+	w.cleanups.Wait()
+	return err
+}
+
+func (w *FileWriter) close() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// open opens the file in append mode, creating it and its directory when missing
+func (w *FileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.filename), 0755); err != nil {
+		return fmt.Errorf("noodlog: unable to create the log directory: %w", err)
+	}
+	file, err := os.OpenFile(w.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("noodlog: unable to open the log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("noodlog: unable to open the log file: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = w.now()
+	if w.size > 0 {
+		w.openedAt = info.ModTime()
+	}
+	return w.link()
+}
+
+// link points the symlink to the file being written
+func (w *FileWriter) link() error {
+	if w.options.Symlink == "" {
+		return nil
+	}
+	target, err := filepath.Abs(w.filename)
+	if err != nil {
+		return err
+	}
+	tmp := w.options.Symlink + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("noodlog: unable to create the symlink: %w", err)
+	}
+	return os.Rename(tmp, w.options.Symlink)
+}
+
+// mustRotate tells if writing n bytes requires a rotation of the file
+func (w *FileWriter) mustRotate(n int64) bool {
+	if w.options.MaxSize > 0 && w.size > 0 && w.size+n > w.options.MaxSize {
+		return true
+	}
+	return !samePeriod(w.openedAt, w.now(), w.options.Rotation)
+}
+
+// samePeriod tells if the two times are in the same rotation period
+func samePeriod(t1, t2 time.Time, rotation Rotation) bool {
+	t1, t2 = t1.In(time.Local), t2.In(time.Local)
+	switch rotation {
+	case DailyRotation:
+		return t1.Format("2006-01-02") == t2.Format("2006-01-02")
+	case HourlyRotation:
+		return t1.Format("2006-01-02T15") == t2.Format("2006-01-02T15")
+	default:
+		return true
+	}
+}
+
+// rotate renames the current file to a backup and opens a new file, then compresses the backup
+// and applies the retention policy in background
+func (w *FileWriter) rotate() error {
+	if err := w.close(); err != nil {
+		return err
+	}
+
+	backup := w.freeBackupName(w.now())
+	if err := os.Rename(w.filename, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("noodlog: unable to rotate the log file: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	if w.options.Compress || w.options.MaxBackups > 0 || w.options.MaxAge > 0 {
+		w.cleanups.Add(1)
+		go w.cleanup(backup)
+	}
+	return nil
+}
+
+// cleanup compresses a rotated file and removes the old ones. The failures don't affect the writes:
+// the file is left uncompressed, and the old files are removed at the following rotation.
+func (w *FileWriter) cleanup(backup string) {
+	defer w.cleanups.Done()
+	w.cleanupMu.Lock()
+	defer w.cleanupMu.Unlock()
+
+	if w.options.Compress {
+		compressFile(backup)
+	}
+	w.removeOldBackups()
+}
+
+// backupName returns the name of the file rotated at time t, with the sequence number seq when positive
+func (w *FileWriter) backupName(t time.Time, seq int) string {
+	ext := filepath.Ext(w.filename)
+	prefix := strings.TrimSuffix(w.filename, ext)
+	ts := t.In(time.Local).Format(backupTimeFormat)
+	if seq > 0 {
+		ts = fmt.Sprintf("%s.%d", ts, seq)
+	}
+	return fmt.Sprintf("%s-%s%s", prefix, ts, ext)
+}
+
+// freeBackupName returns the name of the file rotated at time t, adding a sequence number when the files
+// rotated in the same millisecond already took the name, compressed or not
+func (w *FileWriter) freeBackupName(t time.Time) string {
+	for seq := 0; ; seq++ {
+		name := w.backupName(t, seq)
+		if !isFile(name) && !isFile(name+".gz") {
+			return name
+		}
+	}
+}
+
+// isFile tells if a file, not a directory as for backups, exists at path
+func isFile(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && !info.IsDir()
+}
+
+// backup represents a rotated file with its rotation time and its sequence number in that time
+type backup struct {
+	path string
+	time time.Time
+	seq  int
+}
+
+// backups returns the rotated files, from the newest to the oldest
+func (w *FileWriter) backups() ([]backup, error) {
+	dir := filepath.Dir(w.filename)
+	ext := filepath.Ext(w.filename)
+	prefix := strings.TrimSuffix(filepath.Base(w.filename), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backup
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".gz")
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		seq := 0
+		if len(ts) > len(backupTimeFormat) && ts[len(backupTimeFormat)] == '.' {
+			if seq, err = strconv.Atoi(ts[len(backupTimeFormat)+1:]); err != nil || seq <= 0 {
+				continue
+			}
+			ts = ts[:len(backupTimeFormat)]
+		}
+		if t, err := time.ParseInLocation(backupTimeFormat, ts, time.Local); err == nil {
+			backups = append(backups, backup{path: filepath.Join(dir, e.Name()), time: t, seq: seq})
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].time.Equal(backups[j].time) {
+			return backups[i].time.After(backups[j].time)
+		}
+		return backups[i].seq > backups[j].seq
+	})
+	return backups, nil
+}
+
+// removeOldBackups removes the rotated files exceeding MaxBackups or older than MaxAge
+func (w *FileWriter) removeOldBackups() error {
+	if w.options.MaxBackups <= 0 && w.options.MaxAge <= 0 {
+		return nil
+	}
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	cutoff := w.now().Add(-w.options.MaxAge)
+	for i, b := range backups {
+		tooMany := w.options.MaxBackups > 0 && i >= w.options.MaxBackups
+		tooOld := w.options.MaxAge > 0 && b.time.Before(cutoff)
+		if tooMany || tooOld {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compressFile replaces a file with its gzip compressed version
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// keep the uncompressed file rather than a truncated archive
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
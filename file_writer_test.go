@@ -0,0 +1,199 @@
+package noodlog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a FileWriter clock which can be moved forward by the tests
+func fakeClock(start time.Time) (func() time.Time, func(d time.Duration)) {
+	var mu sync.Mutex
+	now := start
+	return func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return now
+		}, func(d time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			now = now.Add(d)
+		}
+}
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf(errorFmt, "readFile", path, err)
+	}
+	return string(content)
+}
+
+func TestFileWriterSizeRotation(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	now, advance := fakeClock(time.Date(2021, 3, 4, 12, 0, 0, 0, time.Local))
+
+	w := NewFileWriter(filename, FileWriterOptions{MaxSize: 10, MaxBackups: 2})
+	w.now = now
+	defer w.Close()
+
+	for _, rec := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := w.Write([]byte(rec)); err != nil {
+			t.Fatalf(errorFmt, "TestFileWriterSizeRotation", nil, err)
+		}
+		advance(time.Second)
+	}
+
+	w.cleanups.Wait()
+
+	if actual := readFile(t, filename); actual != "fourth\n" {
+		t.Errorf(errorFmt, "TestFileWriterSizeRotation", "fourth", actual)
+	}
+	backups, _ := w.backups()
+	if len(backups) != 2 {
+		t.Fatalf(errorFmt, "TestFileWriterSizeRotation", 2, len(backups))
+	}
+	if actual := readFile(t, backups[0].path); actual != "third\n" {
+		t.Errorf(errorFmt, "TestFileWriterSizeRotation", "third", actual)
+	}
+	if expected := filepath.Join(dir, "app-2021-03-04T12-00-03.000.log"); backups[0].path != expected {
+		t.Errorf(errorFmt, "TestFileWriterSizeRotation", expected, backups[0].path)
+	}
+}
+
+func TestFileWriterSameMillisecondRotation(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	now, _ := fakeClock(time.Date(2021, 3, 4, 12, 0, 0, 0, time.Local))
+
+	w := NewFileWriter(filename, FileWriterOptions{MaxSize: 10, MaxBackups: 2})
+	w.now = now
+	defer w.Close()
+
+	for _, rec := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := w.Write([]byte(rec)); err != nil {
+			t.Fatalf(errorFmt, "TestFileWriterSameMillisecondRotation", nil, err)
+		}
+	}
+
+	w.cleanups.Wait()
+
+	backups, _ := w.backups()
+	if len(backups) != 2 {
+		t.Fatalf(errorFmt, "TestFileWriterSameMillisecondRotation", 2, len(backups))
+	}
+	expected := []struct{ name, content string }{
+		{"app-2021-03-04T12-00-00.000.2.log", "third\n"},
+		{"app-2021-03-04T12-00-00.000.1.log", "second\n"},
+	}
+	for i, e := range expected {
+		if path := filepath.Join(dir, e.name); backups[i].path != path || readFile(t, path) != e.content {
+			t.Errorf(errorFmt, "TestFileWriterSameMillisecondRotation", e.name, backups[i].path)
+		}
+	}
+}
+
+func TestFileWriterTimeRotation(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	now, advance := fakeClock(time.Date(2021, 3, 4, 23, 30, 0, 0, time.Local))
+
+	w := NewFileWriter(filename, FileWriterOptions{Rotation: DailyRotation, Compress: true, MaxAge: 36 * time.Hour})
+	w.now = now
+	defer w.Close()
+
+	w.Write([]byte("day one\n"))
+	advance(time.Hour)
+	w.Write([]byte("day two\n"))
+	w.cleanups.Wait()
+
+	backups, _ := w.backups()
+	if len(backups) != 1 || !strings.HasSuffix(backups[0].path, ".log.gz") {
+		t.Fatalf(errorFmt, "TestFileWriterTimeRotation", "one compressed backup", backups)
+	}
+	f, _ := os.Open(backups[0].path)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf(errorFmt, "TestFileWriterTimeRotation", "gzip file", err)
+	}
+	if content, _ := io.ReadAll(gz); string(content) != "day one\n" {
+		t.Errorf(errorFmt, "TestFileWriterTimeRotation", "day one", string(content))
+	}
+
+	advance(48 * time.Hour)
+	w.Write([]byte("day four\n"))
+	w.cleanups.Wait()
+	if backups, _ := w.backups(); len(backups) != 1 || backups[0].time.Day() != 7 {
+		t.Errorf(errorFmt, "TestFileWriterTimeRotation", "only the last backup", backups)
+	}
+}
+
+func TestFileWriterCompressionFailure(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	now, advance := fakeClock(time.Date(2021, 3, 4, 23, 30, 0, 0, time.Local))
+
+	w := NewFileWriter(filename, FileWriterOptions{Rotation: DailyRotation, Compress: true})
+	w.now = now
+	defer w.Close()
+
+	w.Write([]byte("day one\n"))
+	advance(time.Hour)
+	// a directory in place of the archive makes the compression fail
+	backup := w.backupName(now(), 0)
+	if err := os.Mkdir(backup+".gz", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := w.Write([]byte("day two\n")); err != nil || n != len("day two\n") {
+		t.Errorf(errorFmt, "TestFileWriterCompressionFailure", "the record written", err)
+	}
+	w.cleanups.Wait()
+
+	if actual := readFile(t, filename); actual != "day two\n" {
+		t.Errorf(errorFmt, "TestFileWriterCompressionFailure", "day two", actual)
+	}
+	if actual := readFile(t, backup); actual != "day one\n" {
+		t.Errorf(errorFmt, "TestFileWriterCompressionFailure", "the uncompressed backup", actual)
+	}
+}
+
+func TestSamePeriod(t *testing.T) {
+	t1 := time.Date(2021, 3, 4, 10, 59, 0, 0, time.Local)
+	t2 := time.Date(2021, 3, 4, 11, 0, 0, 0, time.Local)
+	t3 := time.Date(2021, 3, 5, 11, 0, 0, 0, time.Local)
+
+	if !samePeriod(t1, t3, NoTimeRotation) || !samePeriod(t1, t2, DailyRotation) || samePeriod(t2, t3, DailyRotation) {
+		t.Errorf(errorFmt, "TestSamePeriod", "daily periods", t1)
+	}
+	if samePeriod(t1, t2, HourlyRotation) || !samePeriod(t2, t2.Add(time.Minute), HourlyRotation) {
+		t.Errorf(errorFmt, "TestSamePeriod", "hourly periods", t1)
+	}
+}
+
+func TestFileWriterSymlinkAndLogger(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "logs", "app.log")
+	symlink := filepath.Join(dir, "current.log")
+
+	w := NewFileWriter(filename, FileWriterOptions{Symlink: symlink})
+	l := NewLogger().LogWriter(w)
+	l.Info("hello")
+	w.Close()
+
+	if actual := readFile(t, symlink); !strings.Contains(actual, `"message":"hello"`) {
+		t.Errorf(errorFmt, "TestFileWriterSymlinkAndLogger", "hello", actual)
+	}
+	if err := w.Rotate(); err != nil {
+		t.Errorf(errorFmt, "TestFileWriterSymlinkAndLogger", nil, err)
+	}
+	if actual := readFile(t, symlink); actual != "" {
+		t.Errorf(errorFmt, "TestFileWriterSymlinkAndLogger", "empty file", actual)
+	}
+}
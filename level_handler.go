@@ -0,0 +1,148 @@
+package noodlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LevelHandler is an http.Handler reading and changing the level of a Logger while it is logging:
+//
+//	GET returns the current level: {"level":"info"}
+//	PUT changes it: {"level":"debug"}, optionally restoring the previous level after a TTL: {"level":"debug","ttl":"10m"}
+//
+// The changes also reach the loggers derived from the logger with With or Named, unless they set their own level.
+// Every change is recorded with an audit record printed by the logger whatever its level.
+type LevelHandler struct {
+	logger *Logger
+
+	mu       sync.Mutex
+	revert   *time.Timer
+	revertTo Level
+	revertAt time.Time
+	// changes counts the level changes, so that a timer doesn't revert a newer change
+	changes int
+}
+
+// levelState is the body of the LevelHandler responses
+type levelState struct {
+	Level    Level      `json:"level"`
+	RevertTo *Level     `json:"revertTo,omitempty"`
+	RevertAt *time.Time `json:"revertAt,omitempty"`
+}
+
+// levelChange is the body of the PUT requests of the LevelHandler
+type levelChange struct {
+	Level *Level `json:"level"`
+	TTL   string `json:"ttl,omitempty"`
+}
+
+// NewLevelHandler func is the constructor of a LevelHandler controlling the level of l
+func NewLevelHandler(l *Logger) *LevelHandler {
+	return &LevelHandler{logger: l}
+}
+
+// ServeHTTP returns or changes the level of the logger
+func (h *LevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.writeState(w, http.StatusOK)
+	case http.MethodPut:
+		var change levelChange
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			writeHTTPError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+			return
+		}
+		if change.Level == nil {
+			writeHTTPError(w, http.StatusBadRequest, "missing level")
+			return
+		}
+		var ttl time.Duration
+		if change.TTL != "" {
+			var err error
+			if ttl, err = time.ParseDuration(change.TTL); err != nil || ttl <= 0 {
+				writeHTTPError(w, http.StatusBadRequest, fmt.Sprintf("invalid ttl %q", change.TTL))
+				return
+			}
+		}
+		h.setLevel(*change.Level, ttl, r.RemoteAddr)
+		h.writeState(w, http.StatusOK)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeHTTPError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// setLevel changes the level of the logger, scheduling the revert to the level preceding the
+// temporary changes when ttl is positive. A change without ttl cancels the scheduled revert.
+func (h *LevelHandler) setLevel(level Level, ttl time.Duration, remoteAddr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	previous := h.logger.GetLevel()
+	h.changes++
+	if h.revert != nil {
+		h.revert.Stop()
+		h.revert = nil
+	} else {
+		h.revertTo = previous
+	}
+
+	h.logger.SetLevel(level)
+	fields := []Field{{"previousLevel", previous.String()}, {"newLevel", level.String()}, {"remoteAddr", remoteAddr}}
+	if ttl > 0 {
+		h.revertAt = time.Now().Add(ttl)
+		change := h.changes
+		h.revert = time.AfterFunc(ttl, func() { h.expire(change) })
+		fields = append(fields, Field{"ttl", ttl.String()})
+	}
+	h.logger.audit(infoLabel, "log level changed", fields)
+}
+
+// expire restores the level preceding the temporary changes, unless the level has been changed again
+func (h *LevelHandler) expire(change int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.changes != change {
+		return
+	}
+	h.revert = nil
+
+	previous := h.logger.GetLevel()
+	h.logger.SetLevel(h.revertTo)
+	h.logger.audit(infoLabel, "log level reverted", []Field{{"previousLevel", previous.String()}, {"newLevel", h.revertTo.String()}, {"reason", "ttl expired"}})
+}
+
+func (h *LevelHandler) writeState(w http.ResponseWriter, status int) {
+	h.mu.Lock()
+	state := levelState{Level: h.logger.GetLevel()}
+	if h.revert != nil {
+		revertTo, revertAt := h.revertTo, h.revertAt
+		state.RevertTo, state.RevertAt = &revertTo, &revertAt
+	}
+	h.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(state)
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// audit prints a record with the given level label and fields, whatever the level of the logger and of its sinks
+func (l *Logger) audit(label, message string, fields []Field) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entry := l.newEntry(label, l.now())
+	entry.Message = message
+	entry.Fields = l.composeFields(fields)
+	entry.unfiltered = true
+	l.writeEntry(l.maskSecrets(entry), l.level())
+}
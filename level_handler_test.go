@@ -0,0 +1,95 @@
+package noodlog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serveLevel(h http.Handler, method, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, "/log/level", strings.NewReader(body)))
+	return rec
+}
+
+func TestLevelHandler(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).Level(errorLabel)
+	h := NewLevelHandler(l)
+
+	if rec := serveLevel(h, http.MethodGet, ""); rec.Code != http.StatusOK || rec.Body.String() != `{"level":"error"}`+"\n" {
+		t.Errorf(errorFmt, "TestLevelHandler GET", `{"level":"error"}`, rec.Body.String())
+	}
+
+	rec := serveLevel(h, http.MethodPut, `{"level":"DEBUG"}`)
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"level":"debug"}`+"\n" || l.GetLevel() != DebugLevel {
+		t.Errorf(errorFmt, "TestLevelHandler PUT", `{"level":"debug"}`, rec.Body.String())
+	}
+	expected := `{"level":"info","message":"log level changed","time":"*","previousLevel":"error","newLevel":"debug","remoteAddr":"192.0.2.1:1234"}` + "\n"
+	if actual := b.String(); !Matches(actual, expected) {
+		t.Errorf(errorFmt, "TestLevelHandler audit", expected, actual)
+	}
+
+	invalid := map[string]string{
+		`{"level":"verbose"}`:           http.MethodPut,
+		`{"ttl":"1m"}`:                  http.MethodPut,
+		`{"level":"info","ttl":"soon"}`: http.MethodPut,
+		`not json`:                      http.MethodPut,
+	}
+	for body, method := range invalid {
+		if rec := serveLevel(h, method, body); rec.Code != http.StatusBadRequest {
+			t.Errorf(errorFmt, "TestLevelHandler "+body, http.StatusBadRequest, rec.Code)
+		}
+	}
+	if rec := serveLevel(h, http.MethodPost, ""); rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, PUT" {
+		t.Errorf(errorFmt, "TestLevelHandler POST", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+func TestLevelHandlerChildLoggers(t *testing.T) {
+	var b syncBuffer
+	l := NewLogger().LogWriter(&b).Level(warnLabel)
+	child := l.With("requestId", "abc")
+	named := l.Named("db")
+	h := NewLevelHandler(l)
+
+	serveLevel(h, http.MethodPut, `{"level":"debug","ttl":"50ms"}`)
+	child.Debug("child")
+	named.Debug("named")
+	if !strings.Contains(b.String(), `"message":"child"`) || !strings.Contains(b.String(), `"message":"named"`) {
+		t.Errorf(errorFmt, "TestLevelHandlerChildLoggers PUT", "child and named", b.String())
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for child.GetLevel() != WarnLevel && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if actual := child.GetLevel(); actual != WarnLevel {
+		t.Errorf(errorFmt, "TestLevelHandlerChildLoggers revert", WarnLevel, actual)
+	}
+}
+
+func TestLevelHandlerTTL(t *testing.T) {
+	l := NewLogger().LogWriter(&bytes.Buffer{}).Level(warnLabel)
+	h := NewLevelHandler(l)
+
+	serveLevel(h, http.MethodPut, `{"level":"debug","ttl":"1h"}`)
+	rec := serveLevel(h, http.MethodPut, `{"level":"trace","ttl":"50ms"}`)
+	if !Matches(rec.Body.String(), `{"level":"trace","revertTo":"warn","revertAt":"*"}`+"\n") {
+		t.Errorf(errorFmt, "TestLevelHandlerTTL", "trace reverting to warn", rec.Body.String())
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for l.GetLevel() != WarnLevel && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if actual := l.GetLevel(); actual != WarnLevel {
+		t.Errorf(errorFmt, "TestLevelHandlerTTL", WarnLevel, actual)
+	}
+	if rec := serveLevel(h, http.MethodGet, ""); rec.Body.String() != `{"level":"warn"}`+"\n" {
+		t.Errorf(errorFmt, "TestLevelHandlerTTL", `{"level":"warn"}`, rec.Body.String())
+	}
+}
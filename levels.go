@@ -1,5 +1,37 @@
 package noodlog
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level represents the severity of a log record
+type Level int
+
+const (
+	// TraceLevel is the level of the Trace records
+	TraceLevel Level = traceLevel
+	// DebugLevel is the level of the Debug records
+	DebugLevel Level = debugLevel
+	// InfoLevel is the level of the Info records
+	InfoLevel Level = infoLevel
+	// WarnLevel is the level of the Warn records
+	WarnLevel Level = warnLevel
+	// ErrorLevel is the level of the Error records
+	ErrorLevel Level = errorLevel
+	// PanicLevel is the level of the Panic records
+	PanicLevel Level = panicLevel
+	// FatalLevel is the level of the Fatal records
+	FatalLevel Level = fatalLevel
+)
+
+// levelsMu guards logLevels and levelColors, which are extended by RegisterLevel
+var levelsMu sync.RWMutex
+
 var logLevels = map[string]int{
 	traceLabel: traceLevel,
 	debugLabel: debugLevel,
@@ -10,10 +42,224 @@ var logLevels = map[string]int{
 	fatalLabel: fatalLevel,
 }
 
+// levelColors contains the default colors of the registered levels
+var levelColors = map[string]string{}
+
+// levelAliases maps the alternative names accepted by ParseLevel to the level labels
+var levelAliases = map[string]string{
+	"warning":  warnLabel,
+	"err":      errorLabel,
+	"critical": panicLabel,
+}
+
 func getLogLevel(level string) int {
-	logLevel := logLevels[level]
-	if logLevel == 0 {
-		logLevel = infoLevel
+	logLevel, err := ParseLevel(level)
+	if err != nil {
+		return infoLevel
+	}
+	return int(logLevel)
+}
+
+// ParseLevel function converts a level name, built-in or registered, into a Level. The names are case-insensitive and
+// "warning", "err" and "critical" are accepted as aliases of warn, error and panic, unless registered as custom levels.
+// The "Level(35)" form returned by Level.String for an unregistered severity is accepted too.
+func ParseLevel(name string) (Level, error) {
+	label := strings.ToLower(strings.TrimSpace(name))
+
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	if level, ok := logLevels[label]; ok {
+		return Level(level), nil
+	}
+	if alias, ok := levelAliases[label]; ok {
+		return Level(logLevels[alias]), nil
+	}
+	if level, ok := levelNumber(label); ok {
+		return Level(level), nil
+	}
+	return InfoLevel, fmt.Errorf("noodlog: unknown level %q", name)
+}
+
+// RegisterLevel function registers a custom level with the given name and severity, e.g. 35 for a level
+// between info and warn, and optionally its default color. The level can then be used by name in the
+// configurations and with Logger.Log. Registering a level again with the same severity only updates its color,
+// while the names and the severities of the other levels can't be reused.
+func RegisterLevel(name string, level Level, color ...Color) error {
+	label := strings.ToLower(strings.TrimSpace(name))
+	if label == "" || strings.ContainsAny(label, " ()") {
+		return fmt.Errorf("noodlog: invalid level name %q", name)
+	}
+	if level <= 0 {
+		return errors.New("noodlog: the severity of a level must be positive")
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	if severity, ok := logLevels[label]; ok && (Level(severity) != level || isBuiltinLabel(label)) {
+		return fmt.Errorf("noodlog: level %q is already registered with severity %d", label, severity)
+	}
+	for existing, severity := range logLevels {
+		if existing != label && Level(severity) == level {
+			return fmt.Errorf("noodlog: severity %d is already used by level %q", severity, existing)
+		}
+	}
+	logLevels[label] = int(level)
+	if len(color) > 0 {
+		levelColors[label] = color[0].toCode()
+	}
+	return nil
+}
+
+// isBuiltinLabel tells if the label is the one of a built-in level
+func isBuiltinLabel(label string) bool {
+	switch label {
+	case traceLabel, debugLabel, infoLabel, warnLabel, errorLabel, panicLabel, fatalLabel:
+		return true
+	}
+	return false
+}
+
+// levelOf returns the severity of a level label, 0 if the label is unknown
+func levelOf(label string) int {
+	levelsMu.RLock()
+	level, ok := logLevels[label]
+	levelsMu.RUnlock()
+	if ok {
+		return level
+	}
+	level, _ = levelNumber(label)
+	return level
+}
+
+// levelNumber parses the label of an unregistered Level, see Level.String
+func levelNumber(label string) (int, bool) {
+	lower := strings.ToLower(label)
+	if !strings.HasPrefix(lower, "level(") || !strings.HasSuffix(lower, ")") {
+		return 0, false
+	}
+	level, err := strconv.Atoi(lower[len("level(") : len(lower)-1])
+	if err != nil || level <= 0 {
+		return 0, false
+	}
+	return level, true
+}
+
+// levelColor returns the color of a level label: the one set on the logger or the one of its registration
+func levelColor(colorMap map[string]string, label string) string {
+	if color, ok := colorMap[label]; ok {
+		return color
 	}
-	return logLevel
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	return levelColors[label]
+}
+
+// String returns the label of the level, e.g. "info"
+func (l Level) String() string {
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	for label, level := range logLevels {
+		if Level(level) == l {
+			return label
+		}
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// MarshalText marshals the level as its label, so that it is written by name in JSON and YAML configs
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText parses a level name, see ParseLevel
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// Set parses a level name, so that a *Level can be used as a flag.Value
+func (l *Level) Set(name string) error {
+	return l.UnmarshalText([]byte(name))
+}
+
+// sharedLevel holds the level and the level overrides of a logger. The loggers derived from it with With or Named
+// have their own sharedLevel, which follows the changes of the parent until they set their own level or overrides.
+type sharedLevel struct {
+	parent    *sharedLevel
+	level     atomic.Value // int
+	overrides atomic.Value // overrideTable
+}
+
+// overrideTable wraps the level overrides, so that an empty table can be stored in an atomic.Value
+type overrideTable struct {
+	overrides []levelOverride
+}
+
+// newSharedLevel returns the sharedLevel of a root logger
+func newSharedLevel(level int) *sharedLevel {
+	sl := &sharedLevel{}
+	sl.level.Store(level)
+	sl.overrides.Store(overrideTable{})
+	return sl
+}
+
+// child returns the sharedLevel of a logger derived from the one of sl
+func (sl *sharedLevel) child() *sharedLevel {
+	return &sharedLevel{parent: sl}
+}
+
+// get returns the level set on sl or, if not set, on its closest ancestor
+func (sl *sharedLevel) get() int {
+	for ; sl != nil; sl = sl.parent {
+		if level := sl.level.Load(); level != nil {
+			return level.(int)
+		}
+	}
+	return infoLevel
+}
+
+// getOverrides returns the level overrides set on sl or, if not set, on its closest ancestor
+func (sl *sharedLevel) getOverrides() []levelOverride {
+	for ; sl != nil; sl = sl.parent {
+		if table := sl.overrides.Load(); table != nil {
+			return table.(overrideTable).overrides
+		}
+	}
+	return nil
+}
+
+// level returns the level of the logger
+func (s *settings) level() int {
+	return s.levels.get()
+}
+
+// setLevel sets the level of the logger and of the loggers derived from it which don't set their own
+func (s *settings) setLevel(level int) {
+	s.levels.level.Store(level)
+}
+
+// SetLevel function let you establish the log level for a specified logger instance.
+// The loggers derived from it with With or Named follow the change, unless they set their own level.
+func (l *Logger) SetLevel(level Level) *Logger {
+	return l.update(func(s *settings) {
+		s.setLevel(int(level))
+	})
+}
+
+// GetLevel returns the minimum level printed by the logger
+func (l *Logger) GetLevel() Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return Level(l.level())
+}
+
+// Enabled tells if a record with the given level would be printed by the logger or by any of its sinks
+func (l *Logger) Enabled(level Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.isEnabled(level.String())
 }
@@ -1,6 +1,11 @@
 package noodlog
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"strings"
 	"testing"
 )
 
@@ -25,3 +30,127 @@ func TestGetLogLevel(t *testing.T) {
 	}
 
 }
+
+func TestParseLevel(t *testing.T) {
+	testMap := map[string]Level{
+		"trace":     TraceLevel,
+		"DEBUG":     DebugLevel,
+		" Info ":    InfoLevel,
+		"warning":   WarnLevel,
+		"ERR":       ErrorLevel,
+		"critical":  PanicLevel,
+		"fatal":     FatalLevel,
+		"Level(35)": Level(35),
+	}
+
+	for input, expected := range testMap {
+		if actual, err := ParseLevel(input); err != nil || actual != expected {
+			t.Errorf(errorFmt, "TestParseLevel "+input, expected, actual)
+		}
+	}
+	for _, input := range []string{"verbose", "Level(0)", "Level(-5)", "Level(35)x"} {
+		if _, err := ParseLevel(input); err == nil {
+			t.Errorf(errorFmt, "TestParseLevel "+input, "unknown level error", nil)
+		}
+	}
+}
+
+func TestLevelText(t *testing.T) {
+	if actual := WarnLevel.String(); actual != warnLabel {
+		t.Errorf(errorFmt, "TestLevelText", warnLabel, actual)
+	}
+	if actual := Level(42).String(); actual != "Level(42)" {
+		t.Errorf(errorFmt, "TestLevelText", "Level(42)", actual)
+	}
+
+	var config struct {
+		Level Level `json:"level"`
+	}
+	if err := json.Unmarshal([]byte(`{"level":"Warning"}`), &config); err != nil || config.Level != WarnLevel {
+		t.Errorf(errorFmt, "TestLevelText", WarnLevel, config.Level)
+	}
+	if jsn, _ := json.Marshal(config); string(jsn) != `{"level":"warn"}` {
+		t.Errorf(errorFmt, "TestLevelText", `{"level":"warn"}`, string(jsn))
+	}
+	if err := json.Unmarshal([]byte(`{"level":"verbose"}`), &config); err == nil {
+		t.Errorf(errorFmt, "TestLevelText", "unknown level error", nil)
+	}
+}
+
+func TestLevelFlag(t *testing.T) {
+	level := InfoLevel
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&level, "level", "log level")
+
+	if err := fs.Parse([]string{"-level", "debug"}); err != nil || level != DebugLevel {
+		t.Errorf(errorFmt, "TestLevelFlag", DebugLevel, level)
+	}
+}
+
+func TestLoggerLevelAccessors(t *testing.T) {
+	l := NewLogger().Level("ERROR")
+	if actual := l.GetLevel(); actual != ErrorLevel {
+		t.Errorf(errorFmt, "TestLoggerLevelAccessors", ErrorLevel, actual)
+	}
+	if l.Enabled(WarnLevel) || !l.Enabled(ErrorLevel) {
+		t.Errorf(errorFmt, "TestLoggerLevelAccessors", "only error and above enabled", l.GetLevel())
+	}
+
+	l.SetLevel(DebugLevel).AddSink(NewSink(io.Discard).Level(traceLabel))
+	if actual := l.GetLevel(); actual != DebugLevel || !l.Enabled(TraceLevel) {
+		t.Errorf(errorFmt, "TestLoggerLevelAccessors", "trace enabled by the sink", actual)
+	}
+}
+
+func TestRegisterLevel(t *testing.T) {
+	if err := RegisterLevel("notice", 35, NewColor(Cyan)); err != nil {
+		t.Fatalf(errorFmt, "TestRegisterLevel", nil, err)
+	}
+	if err := RegisterLevel("Notice", 35); err != nil {
+		t.Errorf(errorFmt, "TestRegisterLevel", "registration repeated", err)
+	}
+
+	invalid := map[string]Level{"notice": 36, "audit": InfoLevel, "info": 31, "": 37, "two words": 38, "negative": -1}
+	for name, level := range invalid {
+		if err := RegisterLevel(name, level); err == nil {
+			t.Errorf(errorFmt, "TestRegisterLevel "+name, "registration error", nil)
+		}
+	}
+
+	notice, err := ParseLevel("NOTICE")
+	if err != nil || notice != 35 || notice.String() != "notice" {
+		t.Errorf(errorFmt, "TestRegisterLevel", "notice", notice)
+	}
+}
+
+func TestLoggerCustomLevel(t *testing.T) {
+	if err := RegisterLevel("audit", 45); err != nil {
+		t.Fatalf(errorFmt, "TestLoggerCustomLevel", nil, err)
+	}
+	audit, _ := ParseLevel("audit")
+
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).Level("audit").SetSchema(&Schema{LevelFormat: NumericLevels})
+
+	l.Warn("filtered")
+	l.Log(audit, "user %s logged in", "gyoza")
+	l.Log(Level(99), "unregistered")
+	expected := `{"level":45,"message":"user gyoza logged in","time":"*"}` + "\n" + `{"level":99,"message":"unregistered","time":"*"}` + "\n"
+	if actual := b.String(); !Matches(actual, expected) {
+		t.Errorf(errorFmt, "TestLoggerCustomLevel", expected, actual)
+	}
+	b.Reset()
+
+	l.SetSchema(nil).EnableColors()
+	l.SetLevelColor(audit, NewColor(Purple))
+	l.Log(audit, "colored")
+	if actual := b.String(); !strings.HasPrefix(actual, "\033[35m{\"level\":\"audit\"") {
+		t.Errorf(errorFmt, "TestLoggerCustomLevel", "purple audit record", actual)
+	}
+	b.Reset()
+
+	l.Format(consoleFormat).DisableColors().Log(audit, "console")
+	if actual := b.String(); !Matches(actual, "*:*:* AUDIT console\n") {
+		t.Errorf(errorFmt, "TestLoggerCustomLevel", "AUDIT console", actual)
+	}
+}
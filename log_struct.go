@@ -1,6 +1,9 @@
 package noodlog
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // record struct represents the schema for every log record
 type record struct {
@@ -8,13 +11,24 @@ type record struct {
 	File     *string     `json:"file,omitempty"`
 	Function *string     `json:"function,omitempty"`
 	Message  interface{} `json:"message,omitempty"`
-	Time     string      `json:"time,omitempty"`
+	Time     interface{} `json:"time,omitempty"`
+	Fields   []Field     `json:"-"`
 }
 
 // Configs struct contains all possible configs for noodlog
 type Configs struct {
 	LogLevel             *string
+	LevelOverrides       map[string]string
 	LogWriter            io.Writer
+	Async                *bool
+	AsyncOptions         *AsyncOptions
+	Format               *string
+	Encoder              Encoder
+	Preset               *string
+	TimeFormat           *string
+	Schema               *Schema
+	UTC                  *bool
+	Clock                func() time.Time
 	JSONPrettyPrint      *bool
 	TraceCaller          *bool
 	SinglePointTracing   *bool
@@ -22,15 +36,23 @@ type Configs struct {
 	CustomColors         *CustomColors
 	ObscureSensitiveData *bool
 	SensitiveParams      []string
+	Redactions           map[string]Redaction
+	DefaultRedaction     Redaction
+	DetectSecrets        *bool
+	Detectors            []Detector
+	Sinks                []*Sink
+	ContextExtractors    []ContextExtractor
 }
 
-// CustomColors struct is used to specify the custom colors for the various log levels
+// CustomColors struct is used to specify the custom colors for the various log levels.
+// Levels contains the colors of the other levels by name, e.g. panic, fatal or the levels added with RegisterLevel.
 type CustomColors struct {
-	Trace interface{}
-	Debug interface{}
-	Info  interface{}
-	Warn  interface{}
-	Error interface{}
+	Trace  interface{}
+	Debug  interface{}
+	Info   interface{}
+	Warn   interface{}
+	Error  interface{}
+	Levels map[string]interface{}
 }
 
 // ~~~~~~~~~~~ Prebuilt pointers to be used in the SetConfigs ~~~~~~~~~~~~ //
@@ -50,6 +72,39 @@ var LevelWarn = pointerOfString(warnLabel)
 // LevelError pointer for the Config struct
 var LevelError = pointerOfString(errorLabel)
 
+// FormatJSON pointer for the Config struct
+var FormatJSON = pointerOfString(jsonFormat)
+
+// FormatLogfmt pointer for the Config struct
+var FormatLogfmt = pointerOfString(logfmtFormat)
+
+// FormatConsole pointer for the Config struct
+var FormatConsole = pointerOfString(consoleFormat)
+
+// PresetGCP pointer for the Config struct
+var PresetGCP = pointerOfString(gcpPreset)
+
+// PresetECS pointer for the Config struct
+var PresetECS = pointerOfString(ecsPreset)
+
+// PresetDatadog pointer for the Config struct
+var PresetDatadog = pointerOfString(datadogPreset)
+
+// TimeFormatRFC3339 pointer for the Config struct
+var TimeFormatRFC3339 = pointerOfString(rfc3339TimeFormat)
+
+// TimeFormatRFC3339Nano pointer for the Config struct
+var TimeFormatRFC3339Nano = pointerOfString(rfc3339NanoTimeFormat)
+
+// TimeFormatUnix pointer for the Config struct
+var TimeFormatUnix = pointerOfString(unixTimeFormat)
+
+// TimeFormatUnixMilli pointer for the Config struct
+var TimeFormatUnixMilli = pointerOfString(unixMilliTimeFormat)
+
+// TimeFormatUnixNano pointer for the Config struct
+var TimeFormatUnixNano = pointerOfString(unixNanoTimeFormat)
+
 // Enable pointer for the Config struct
 var Enable = pointerOfBool(true)
 
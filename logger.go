@@ -1,293 +1,446 @@
 package noodlog
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
-// Logger represent the logger object
+// Logger represent the logger object.
+// A Logger can be reconfigured and used for logging from multiple goroutines at the same time.
 type Logger struct {
-	level                int
+	mu      sync.RWMutex
+	writeMu *sync.Mutex
+	settings
+}
+
+// settings contains the configuration of a Logger, guarded by the Logger mutex
+type settings struct {
+	levels               *sharedLevel
+	name                 string
 	logWriter            io.Writer
+	encoder              Encoder
+	timeFormat           string
+	schema               *Schema
+	utc                  bool
+	clock                func() time.Time
 	prettyPrint          bool
 	traceCaller          bool
 	traceCallerLevel     int
 	obscureSensitiveData bool
 	sensitiveParams      []string
+	redactions           map[string]Redaction
+	defaultRedaction     Redaction
+	detectSecrets        bool
+	detectors            []Detector
+	secretCounters       *secretCounters
 	colors               bool
 	colorMap             map[string]string
+	fields               []Field
+	contextExtractors    []ContextExtractor
+	handler              entryHandler
+	sinks                []Sink
+	async                *asyncQueue
+	ownsAsync            bool
+	dropped              uint64
+}
+
+// entryHandler receives the entries of a logger in place of its encoder and writer
+type entryHandler interface {
+	handleEntry(entry Entry)
 }
 
 // NewLogger func is the default constructor of a Logger
 func NewLogger() *Logger {
 	return &Logger{
-		level:                infoLevel,
-		logWriter:            os.Stdout,
-		prettyPrint:          false,
-		traceCaller:          false,
-		traceCallerLevel:     5,
-		obscureSensitiveData: false,
-		sensitiveParams:      nil,
-		colors:               false,
-		colorMap:             colorMap,
+		writeMu: &sync.Mutex{},
+		settings: settings{
+			levels:               newSharedLevel(infoLevel),
+			logWriter:            os.Stdout,
+			prettyPrint:          false,
+			traceCaller:          false,
+			traceCallerLevel:     5,
+			obscureSensitiveData: false,
+			sensitiveParams:      nil,
+			colors:               false,
+			colorMap:             copyColorMap(colorMap),
+		},
 	}
 }
 
+// clone returns a copy of the logger which owns its settings and shares the write lock with l
+func (l *Logger) clone() *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	s := l.settings
+	s.colorMap = copyColorMap(l.colorMap)
+	s.levels = l.levels.child()
+	// the asynchronous queue is shared with the child, but only closed by the logger which created it
+	s.ownsAsync = false
+	return &Logger{writeMu: l.writeMu, settings: s}
+}
+
+// update applies fn to the logger settings while holding the write lock
+func (l *Logger) update(fn func(s *settings)) *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fn(&l.settings)
+	return l
+}
+
 // SetConfigs function allows you to rewrite all the configs at once
 func (l *Logger) SetConfigs(configs Configs) *Logger {
-	if configs.LogLevel != nil {
-		l.Level(*configs.LogLevel)
-	}
-	if configs.LogWriter != nil {
-		l.LogWriter(configs.LogWriter)
-	}
-	if configs.JSONPrettyPrint != nil {
-		if *configs.JSONPrettyPrint {
-			l.EnableJSONPrettyPrint()
-		} else {
-			l.DisableJSONPrettyPrint()
-		}
-	}
-	if configs.TraceCaller != nil {
-		if *configs.TraceCaller {
-			l.EnableTraceCaller()
-		} else {
-			l.DisableTraceCaller()
-		}
-	}
-	if configs.SinglePointTracing != nil {
-		if *configs.SinglePointTracing {
-			l.EnableSinglePointTracing()
-		} else {
-			l.DisableSinglePointTracing()
-		}
-	}
-	if configs.Colors != nil {
-		if *configs.Colors {
-			l.EnableColors()
-		} else {
-			l.DisableColors()
-		}
-	}
-	if configs.CustomColors != nil {
-		l.SetCustomColors(*configs.CustomColors)
-	}
-	if configs.ObscureSensitiveData != nil {
-		if *configs.ObscureSensitiveData {
-			l.EnableObscureSensitiveData(nil)
-		} else {
-			l.DisableObscureSensitiveData()
-		}
-	}
-	if configs.SensitiveParams != nil {
-		l.SetSensitiveParams(configs.SensitiveParams)
-	}
-	return l
+	return l.update(func(s *settings) {
+		s.apply(configs)
+	})
 }
 
 // Level func let you establish the log level for a specified logger instance
 func (l *Logger) Level(level string) *Logger {
-	l.level = getLogLevel(level)
-	return l
+	return l.update(func(s *settings) {
+		s.setLevel(getLogLevel(level))
+	})
 }
 
 // LogWriter function let you define a logWriter (os.Stdout, a file, a buffer etc.)
 func (l *Logger) LogWriter(w io.Writer) *Logger {
-	l.logWriter = w
-	return l
+	return l.update(func(s *settings) {
+		s.logWriter = w
+	})
+}
+
+// Format function let you choose one of the built-in formats: "json" (default), "logfmt" or "console"
+func (l *Logger) Format(format string) *Logger {
+	return l.update(func(s *settings) {
+		s.setFormat(format)
+	})
+}
+
+// SetEncoder function let you define a custom Encoder for the log records
+func (l *Logger) SetEncoder(encoder Encoder) *Logger {
+	return l.update(func(s *settings) {
+		s.encoder = encoder
+	})
+}
+
+// TimeFormat function let you choose the format of the record time: "RFC3339", "RFC3339Nano",
+// "Unix", "UnixMilli" and "UnixNano" (as numbers) or a custom time layout
+func (l *Logger) TimeFormat(format string) *Logger {
+	return l.update(func(s *settings) {
+		s.timeFormat = format
+	})
+}
+
+// EnableUTC function let you print the record time in UTC instead of the local time
+func (l *Logger) EnableUTC() *Logger {
+	return l.update(func(s *settings) {
+		s.utc = true
+	})
+}
+
+// DisableUTC function let you print the record time in the local time
+func (l *Logger) DisableUTC() *Logger {
+	return l.update(func(s *settings) {
+		s.utc = false
+	})
+}
+
+// SetClock function let you define the function returning the time of the records, nil restores time.Now
+func (l *Logger) SetClock(clock func() time.Time) *Logger {
+	return l.update(func(s *settings) {
+		s.clock = clock
+	})
 }
 
 // EnableJSONPrettyPrint func let you enable JSON pretty printing for the specified logger instance
 func (l *Logger) EnableJSONPrettyPrint() *Logger {
-	l.prettyPrint = true
-	return l
+	return l.update(func(s *settings) {
+		s.prettyPrint = true
+	})
 }
 
 // DisableJSONPrettyPrint func let you disable JSON pretty printing for the specified logger instance
 func (l *Logger) DisableJSONPrettyPrint() *Logger {
-	l.prettyPrint = false
-	return l
+	return l.update(func(s *settings) {
+		s.prettyPrint = false
+	})
 }
 
 // EnableTraceCaller enables the tracing of the caller for the specified logger instance
 func (l *Logger) EnableTraceCaller() *Logger {
-	l.traceCaller = true
-	return l
+	return l.update(func(s *settings) {
+		s.traceCaller = true
+	})
 }
 
 // DisableTraceCaller disables the tracing of the caller for the specified logger instance
 func (l *Logger) DisableTraceCaller() *Logger {
-	l.traceCaller = false
-	return l
+	return l.update(func(s *settings) {
+		s.traceCaller = false
+	})
 }
 
 // EnableSinglePointTracing function enables tracing the caller when setting the logger in a single package for the whole project and recalling the logging for the project from that single point for the specified logger instance
 func (l *Logger) EnableSinglePointTracing() *Logger {
-	l.traceCaller = true
-	l.traceCallerLevel = 6
-	return l
+	return l.update(func(s *settings) {
+		s.setSinglePointTracing(true)
+	})
 }
 
 // DisableSinglePointTracing function trace function and filename of the directl caller
 func (l *Logger) DisableSinglePointTracing() *Logger {
-	l.traceCallerLevel = 5
-	return l
+	return l.update(func(s *settings) {
+		s.setSinglePointTracing(false)
+	})
 }
 
 // EnableColors function let you enable colored logs for a specified logger
 func (l *Logger) EnableColors() *Logger {
-	l.colors = true
-	return l
+	return l.update(func(s *settings) {
+		s.colors = true
+	})
 }
 
 // DisableColors function let you disable colored logs for a specified logger
 func (l *Logger) DisableColors() *Logger {
-	l.colors = false
-	return l
+	return l.update(func(s *settings) {
+		s.colors = false
+	})
 }
 
 // SetCustomColors overrides defaultColor when custom color is passed into CustomColor configs
 func (l *Logger) SetCustomColors(colors CustomColors) *Logger {
-
-	empty := Color{}
-	if traceColor := detectColor(colors.Trace); traceColor != empty {
-		l.SetTraceColor(traceColor)
-	}
-	if debugColor := detectColor(colors.Debug); debugColor != empty {
-		l.SetDebugColor(debugColor)
-	}
-	if infoColor := detectColor(colors.Info); infoColor != empty {
-		l.SetInfoColor(infoColor)
-	}
-	if warnColor := detectColor(colors.Warn); warnColor != empty {
-		l.SetWarnColor(warnColor)
-	}
-	if errorColor := detectColor(colors.Error); errorColor != empty {
-		l.SetErrorColor(errorColor)
-	}
-
-	return l
+	return l.update(func(s *settings) {
+		s.setCustomColors(colors)
+	})
 }
 
 // SetTraceColor overrides the trace level log color with the one specified in input
 func (l *Logger) SetTraceColor(color Color) {
-	l.colorMap[traceLabel] = color.toCode()
+	l.setColor(traceLabel, color)
 }
 
 // SetDebugColor overrides the debug level log color with the one specified in input
 func (l *Logger) SetDebugColor(color Color) {
-	l.colorMap[debugLabel] = color.toCode()
+	l.setColor(debugLabel, color)
 }
 
 // SetInfoColor overrides the info level log color with the one specified in input
 func (l *Logger) SetInfoColor(color Color) {
-	l.colorMap[infoLabel] = color.toCode()
+	l.setColor(infoLabel, color)
 }
 
 // SetWarnColor overrides the warn level log color with the one specified in input
 func (l *Logger) SetWarnColor(color Color) {
-	l.colorMap[warnLabel] = color.toCode()
+	l.setColor(warnLabel, color)
 }
 
 // SetErrorColor overrides the error level log color with the one specified in input
 func (l *Logger) SetErrorColor(color Color) {
-	l.colorMap[errorLabel] = color.toCode()
+	l.setColor(errorLabel, color)
+}
+
+// SetLevelColor overrides the color of a level, built-in or registered with RegisterLevel, with the one specified in input
+func (l *Logger) SetLevelColor(level Level, color Color) {
+	l.setColor(level.String(), color)
+}
+
+func (l *Logger) setColor(label string, color Color) {
+	l.update(func(s *settings) {
+		s.colorMap[label] = color.toCode()
+	})
 }
 
 // EnableObscureSensitiveData enables sensitive data obscuration from json logs for a given logger instance
 func (l *Logger) EnableObscureSensitiveData(params []string) *Logger {
-	l.obscureSensitiveData = true
-	l.sensitiveParams = params
-	return l
+	return l.update(func(s *settings) {
+		s.obscureSensitiveData = true
+		s.setSensitiveParams(params)
+	})
 }
 
 // DisableObscureSensitiveData disables sensitive data obscuration from json logs for a given logger instance
 func (l *Logger) DisableObscureSensitiveData() *Logger {
-	l.obscureSensitiveData = false
-	return l
+	return l.update(func(s *settings) {
+		s.obscureSensitiveData = false
+	})
 }
 
 // SetSensitiveParams sets sensitive data obscuration from json logs
 func (l *Logger) SetSensitiveParams(params []string) *Logger {
-	l.sensitiveParams = params
-	return l
+	return l.update(func(s *settings) {
+		s.setSensitiveParams(params)
+	})
 }
 
 // Trace function prints a log with trace log level
 func (l *Logger) Trace(message ...interface{}) {
-	l.printLog(traceLabel, message)
+	l.printLog(nil, traceLabel, message)
 }
 
 // Debug function prints a log with debug log level
 func (l *Logger) Debug(message ...interface{}) {
-	l.printLog(debugLabel, message)
+	l.printLog(nil, debugLabel, message)
 }
 
 // Info function prints a log with info log level
 func (l *Logger) Info(message ...interface{}) {
-	l.printLog(infoLabel, message)
+	l.printLog(nil, infoLabel, message)
 }
 
 // Warn function prints a log with warn log level
 func (l *Logger) Warn(message ...interface{}) {
-	l.printLog(warnLabel, message)
+	l.printLog(nil, warnLabel, message)
 }
 
 // Error function prints a log with error log level
 func (l *Logger) Error(message ...interface{}) {
-	l.printLog(errorLabel, message)
+	l.printLog(nil, errorLabel, message)
 }
 
 // Panic function prints a log with panic log level
 func (l *Logger) Panic(message ...interface{}) {
-	panic(l.composeLog(panicLabel, message))
+	l.mu.RLock()
+	logRecord := l.composeLog(nil, panicLabel, l.composeMessage(message), nil)
+	l.mu.RUnlock()
+	panic(logRecord)
 }
 
 // Fatal function prints a log with fatal log level
 func (l *Logger) Fatal(message ...interface{}) {
-	l.printLog(fatalLabel, message)
+	l.printLog(nil, fatalLabel, message)
+	l.exit()
+}
+
+// Log function prints a log with the given level, built-in or registered with RegisterLevel.
+// Unlike Panic and Fatal, it never panics nor exits.
+func (l *Logger) Log(level Level, message ...interface{}) {
+	l.printLog(nil, level.String(), message)
+}
+
+// exit terminates the program after delivering the queued records, unless EXIT_ON_FATAL_DISABLED is true
+func (l *Logger) exit() {
 	if os.Getenv("EXIT_ON_FATAL_DISABLED") != "true" {
+		l.Flush()
+		l.Close()
 		os.Exit(1)
 	}
 }
 
-func (l *Logger) printLog(label string, message []interface{}) {
-	if logLevels[label] >= l.level {
-		fmt.Fprintln(l.logWriter, l.composeLog(label, message))
+// printLog prints a record with the fields extracted from ctx, which is nil when logging without context
+func (l *Logger) printLog(ctx context.Context, label string, message []interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if level := l.callerLevel(); l.isEnabledAt(label, level) {
+		l.writeEntry(l.composeEntry(ctx, label, l.composeMessage(message), nil), level)
 	}
 }
 
-func (l *Logger) composeLog(level string, message []interface{}) string {
+// printRecord prints a record coming from another logging API, tracing the caller from its program counter
+func (l *Logger) printRecord(ctx context.Context, label string, t time.Time, pc uintptr, message interface{}, fields []Field) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 
-	logMsg := record{
-		Level:   level,
-		Message: l.composeMessage(message),
-		Time:    strings.Split(time.Now().String(), "m")[0],
+	level := l.level()
+	if pc != 0 {
+		_, function := callerOf(pc)
+		level = l.levelFor(function)
+	}
+	if !l.isEnabledAt(label, level) {
+		return
+	}
+	entry := l.newEntry(label, t)
+	entry.Message = l.adaptMessage(message)
+	entry.Fields = l.composeFields(mergeFields(l.contextFields(ctx), fields))
+	if l.traceCaller && pc != 0 {
+		entry.File, entry.Function = callerOf(pc)
 	}
+	l.writeEntry(l.maskSecrets(entry), level)
+}
+
+// isEnabled tells if a record with the given level label could be printed by any output, whatever its caller;
+// the caller must hold the read lock
+func (l *Logger) isEnabled(label string) bool {
+	return l.isEnabledAt(label, l.lowestLevel())
+}
+
+// isEnabledAt tells if a record with the given level label would be printed by any output, given the level of the logger
+// for the record; the caller must hold the read lock
+func (l *Logger) isEnabledAt(label string, level int) bool {
+	return levelOf(label) >= l.minLevel(level)
+}
+
+// composeLog builds the log record; the caller must hold the read lock
+func (l *Logger) composeLog(ctx context.Context, level string, message interface{}, fields []Field) string {
+	return l.encode(l.composeEntry(ctx, level, message, fields))
+}
+
+// composeEntry builds the entry of a record with the composed message and the fields of the record;
+// the caller must hold the read lock
+func (l *Logger) composeEntry(ctx context.Context, level string, message interface{}, fields []Field) Entry {
+	entry := l.newEntry(level, l.now())
+	entry.Message = message
+	entry.Fields = l.composeFields(mergeFields(l.contextFields(ctx), fields))
 
 	if l.traceCaller {
-		f, fx := traceCaller(l.traceCallerLevel)
-		logMsg.File = &f
-		logMsg.Function = &fx
+		entry.File, entry.Function = traceCaller(l.traceCallerLevel)
 	}
+	return l.maskSecrets(entry)
+}
 
-	var jsn []byte
-	if l.prettyPrint {
-		jsn, _ = json.MarshalIndent(logMsg, "", "   ")
-	} else {
-		jsn, _ = json.Marshal(logMsg)
+// writeEntry hands the entry to the sink handler or writes it to the outputs, given the level of the logger for the record;
+// the caller must hold the read lock
+func (l *Logger) writeEntry(entry Entry, level int) {
+	if l.handler != nil {
+		l.handler.handleEntry(l.defaultOutput().obscure(entry))
+		return
 	}
 
-	logRecord := string(jsn)
-	if l.colors {
-		logRecord = fmt.Sprintf("%s%s%s", l.colorMap[level], logRecord, colorReset)
+	for _, o := range l.outputs(level) {
+		if !entry.unfiltered && levelOf(entry.Level) < o.level {
+			continue
+		}
+		logRecord := o.encode(entry, l.colorMap)
+		w := l.writerFor(o.writer)
+
+		l.writeMu.Lock()
+		fmt.Fprintln(w, logRecord)
+		l.writeMu.Unlock()
 	}
+}
 
-	return logRecord
+// encode converts the entry into a record with the logger encoder, obscuring and colors
+func (l *Logger) encode(entry Entry) string {
+	return l.defaultOutput().encode(entry, l.colorMap)
+}
+
+// composeFields merges the extra fields into the logger ones and adapts their values
+func (l *Logger) composeFields(extra []Field) []Field {
+	merged := l.fields
+	if len(extra) != 0 {
+		merged = mergeFields(l.fields, extra)
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	fields := make([]Field, len(merged))
+	for i, f := range merged {
+		fields[i] = Field{Key: f.Key, Value: adaptField(f.Value)}
+	}
+	return fields
+}
+
+func adaptField(value interface{}) interface{} {
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+	return redactTagged(value)
 }
 
 func (l *Logger) composeMessage(message []interface{}) interface{} {
@@ -297,6 +450,7 @@ func (l *Logger) composeMessage(message []interface{}) interface{} {
 	case 1:
 		return l.adaptMessage(message[0])
 	default:
+		message = redactArgs(message)
 		switch message[0].(type) {
 		case string:
 			msg0 := message[0].(string)
@@ -308,20 +462,23 @@ func (l *Logger) composeMessage(message []interface{}) interface{} {
 	}
 }
 
+// adaptMessage converts JSON strings into objects, errors into their text and applies the noodlog struct tags,
+// the sensitive params are obscured by the outputs
 func (l *Logger) adaptMessage(message interface{}) interface{} {
 	switch message.(type) {
 	case string:
-		if l.obscureSensitiveData && len(l.sensitiveParams) != 0 {
-			return strToObj(obscureParams(message.(string), l.sensitiveParams))
-		}
 		return strToObj(message.(string))
 	case error:
 		return message.(error).Error()
-	default:
-		if l.obscureSensitiveData && len(l.sensitiveParams) != 0 {
-			jsn, _ := json.Marshal(message)
-			return strToObj(obscureParams(string(jsn), l.sensitiveParams))
-		}
 	}
-	return message
+	return redactTagged(message)
+}
+
+// redactArgs applies the noodlog struct tags and the Redactor interface to the arguments of a message
+func redactArgs(args []interface{}) []interface{} {
+	redacted := make([]interface{}, len(args))
+	for i, arg := range args {
+		redacted[i] = redactTagged(arg)
+	}
+	return redacted
 }
@@ -2,19 +2,21 @@ package noodlog
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 )
 
 var errorFmt string = "%s failed: expected %v, got %v"
 
-var defaultLogger = Logger{
-	level:                infoLevel,
+var defaultLogger = settings{
+	levels:               newSharedLevel(infoLevel),
 	logWriter:            os.Stdout,
 	prettyPrint:          false,
 	traceCaller:          false,
@@ -25,8 +27,8 @@ var defaultLogger = Logger{
 	colorMap:             colorMap,
 }
 
-var customLogger = Logger{
-	level:                errorLevel,
+var customLogger = settings{
+	levels:               newSharedLevel(errorLevel),
 	logWriter:            os.Stderr,
 	prettyPrint:          true,
 	traceCaller:          true,
@@ -38,6 +40,12 @@ var customLogger = Logger{
 }
 
 func toStr(obj interface{}) string {
+	if s, ok := obj.(settings); ok {
+		// the shared level is compared by value
+		level := s.level()
+		s.levels = nil
+		return fmt.Sprintf("%d %v", level, s)
+	}
 	return fmt.Sprintf("%v", obj)
 }
 
@@ -62,7 +70,7 @@ func Matches(value string, pattern string) bool {
 func TestNewLogger(t *testing.T) {
 
 	expected := toStr(defaultLogger)
-	actual := toStr(*NewLogger())
+	actual := toStr(NewLogger().settings)
 
 	if actual != expected {
 		t.Errorf(errorFmt, "TestNewLogger", expected, actual)
@@ -71,7 +79,7 @@ func TestNewLogger(t *testing.T) {
 
 func TestSetConfigsEmptyConfigs(t *testing.T) {
 	expected := toStr(defaultLogger)
-	actual := toStr(*NewLogger().SetConfigs(Configs{}))
+	actual := toStr(NewLogger().SetConfigs(Configs{}).settings)
 
 	if actual != expected {
 		t.Errorf(errorFmt, "TestSetConfigsEmptyConfigs", expected, actual)
@@ -80,7 +88,7 @@ func TestSetConfigsEmptyConfigs(t *testing.T) {
 
 func TestSetConfigsFullConfigsAllEnabled(t *testing.T) {
 	expected := toStr(customLogger)
-	actual := toStr(*NewLogger().SetConfigs(Configs{
+	actual := toStr(NewLogger().SetConfigs(Configs{
 		LogLevel:             LevelError,
 		LogWriter:            os.Stderr,
 		JSONPrettyPrint:      Enable,
@@ -90,7 +98,7 @@ func TestSetConfigsFullConfigsAllEnabled(t *testing.T) {
 		CustomColors:         &CustomColors{Trace: Color{}, Debug: Green},
 		ObscureSensitiveData: Enable,
 		SensitiveParams:      []string{"password"},
-	}))
+	}).settings)
 
 	if actual != expected {
 		t.Errorf(errorFmt, "TestSetConfigsFullConfigsAllEnabled", expected, actual)
@@ -106,7 +114,7 @@ func TestSetConfigsFullConfigsAllDisabled(t *testing.T) {
 	customLogger.obscureSensitiveData = false
 
 	expected := toStr(customLogger)
-	actual := toStr(*NewLogger().SetConfigs(Configs{
+	actual := toStr(NewLogger().SetConfigs(Configs{
 		LogLevel:             LevelError,
 		LogWriter:            os.Stderr,
 		JSONPrettyPrint:      Disable,
@@ -115,7 +123,7 @@ func TestSetConfigsFullConfigsAllDisabled(t *testing.T) {
 		Colors:               Disable,
 		ObscureSensitiveData: Disable,
 		SensitiveParams:      []string{"password"},
-	}))
+	}).settings)
 
 	if actual != expected {
 		t.Errorf(errorFmt, "TestSetConfigsFullConfigsAllDisabled", expected, actual)
@@ -134,8 +142,8 @@ func TestLevel(t *testing.T) {
 	}
 
 	for input, expected := range testMap {
-		if l.Level(input).level != expected {
-			t.Errorf(errorFmt, "TestLevel", expected, l.level)
+		if l.Level(input).level() != expected {
+			t.Errorf(errorFmt, "TestLevel", expected, l.level())
 		}
 	}
 }
@@ -469,3 +477,50 @@ func TestAdaptMessage(t *testing.T) {
 	}
 
 }
+
+func TestColorMapIsOwnedByLogger(t *testing.T) {
+	l1 := NewLogger()
+	l2 := NewLogger()
+
+	l1.SetTraceColor(NewColor(Red))
+	if l2.colorMap[traceLabel] == l1.colorMap[traceLabel] {
+		t.Errorf(errorFmt, "TestColorMapIsOwnedByLogger", colorMap[traceLabel], l2.colorMap[traceLabel])
+	}
+	if colorMap[traceLabel] != colorReset {
+		t.Errorf(errorFmt, "TestColorMapIsOwnedByLogger", colorReset, colorMap[traceLabel])
+	}
+}
+
+func TestConcurrentConfigsAndLogging(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).EnableObscureSensitiveData([]string{"password"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			l.SetConfigs(Configs{LogLevel: LevelInfo, Colors: Disable, SensitiveParams: []string{"password"}})
+			l.SetErrorColor(NewColor(Red))
+		}()
+		go func() {
+			defer wg.Done()
+			l.Info(`{"user": "gyoza", "password": "Sup3rS3cr3t"}`)
+		}()
+		go func(i int) {
+			defer wg.Done()
+			l.With("goroutine", i).Error("concurrent", "record")
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 20 {
+		t.Errorf(errorFmt, "TestConcurrentConfigsAndLogging", 20, len(lines))
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf(errorFmt, "TestConcurrentConfigsAndLogging", "valid JSON record", line)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package noodlog
+
+import (
+	"fmt"
+	pathpkg "path"
+	"sort"
+	"strings"
+)
+
+// loggerKey is the key of the field containing the name of a named logger
+const loggerKey = "logger"
+
+// levelOverride sets the level of the loggers whose name, or the package of whose caller, matches the pattern
+type levelOverride struct {
+	pattern string
+	level   int
+}
+
+// Named returns a child logger which emits its name in the "logger" field of every record.
+// The names of nested loggers are joined with dots: Named("db").Named("pool") is named "db.pool".
+func (l *Logger) Named(name string) *Logger {
+	child := l.clone()
+	if child.name != "" && name != "" {
+		name = child.name + "." + name
+	} else if name == "" {
+		name = child.name
+	}
+	child.name = name
+	if name != "" {
+		child.fields = mergeFields(child.fields, []Field{{Key: loggerKey, Value: name}})
+	}
+	return child
+}
+
+// SetLevelOverrides function let you set the level of the named loggers and of the packages, overriding the level of
+// the logger. The keys are glob patterns (see path.Match) matched against the logger names, e.g. "db" or "http.*", and
+// against the package paths of the callers, e.g. "github.com/acme/app/store/*". A name override also applies to the
+// loggers nested in it, the name overrides take precedence over the package ones and the longest matching pattern wins.
+// Unknown levels are ignored, nil or an empty map removes the overrides.
+// The loggers derived from l with With or Named follow the change, unless they set their own overrides.
+func (l *Logger) SetLevelOverrides(overrides map[string]string) *Logger {
+	return l.update(func(s *settings) {
+		s.setLevelOverrides(overrides)
+	})
+}
+
+// setLevelOverrides stores the overrides sorted by decreasing pattern length, so that the first match is the longest
+func (s *settings) setLevelOverrides(overrides map[string]string) {
+	var table []levelOverride
+	for pattern, name := range overrides {
+		level, err := ParseLevel(name)
+		if err != nil || pattern == "" {
+			continue
+		}
+		table = append(table, levelOverride{pattern: pattern, level: int(level)})
+	}
+	sort.Slice(table, func(i, j int) bool {
+		a, b := table[i].pattern, table[j].pattern
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+	s.levels.overrides.Store(overrideTable{overrides: table})
+}
+
+// levelOverrides returns the level overrides of the logger
+func (s *settings) levelOverrides() []levelOverride {
+	return s.levels.getOverrides()
+}
+
+// ParseLevelOverrides function parses a comma separated list of pattern=level pairs, e.g. "db=trace,http=warn",
+// into the overrides accepted by SetLevelOverrides
+func ParseLevelOverrides(spec string) (map[string]string, error) {
+	overrides := map[string]string{}
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		i := strings.Index(pair, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("noodlog: invalid level override %q, expected pattern=level", pair)
+		}
+		pattern, level := strings.TrimSpace(pair[:i]), strings.TrimSpace(pair[i+1:])
+		if _, err := pathpkg.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, fmt.Errorf("noodlog: invalid pattern in level override %q", pair)
+		}
+		if _, err := ParseLevel(level); err != nil {
+			return nil, err
+		}
+		overrides[pattern] = level
+	}
+	return overrides, nil
+}
+
+// callerLevel returns the level of the logger for a record logged by the caller of the public logging method.
+// It must be called directly by the printLog functions, so that the caller is found at the same depth as in composeEntry.
+func (l *Logger) callerLevel() int {
+	overrides := l.levelOverrides()
+	if len(overrides) == 0 {
+		return l.level()
+	}
+	if level, ok := l.nameLevel(overrides); ok {
+		return level
+	}
+	_, function := traceCaller(l.traceCallerLevel)
+	return l.packageLevel(overrides, function)
+}
+
+// levelFor returns the level of the logger for a record logged by the given function
+func (l *Logger) levelFor(function string) int {
+	overrides := l.levelOverrides()
+	if level, ok := l.nameLevel(overrides); ok {
+		return level
+	}
+	return l.packageLevel(overrides, function)
+}
+
+// nameLevel returns the level of the first override matching the name of the logger or one of its parents
+func (l *Logger) nameLevel(overrides []levelOverride) (int, bool) {
+	if l.name == "" {
+		return 0, false
+	}
+	for _, o := range overrides {
+		for name := l.name; ; name = name[:strings.LastIndex(name, ".")] {
+			if ok, _ := pathpkg.Match(o.pattern, name); ok {
+				return o.level, true
+			}
+			if !strings.Contains(name, ".") {
+				break
+			}
+		}
+	}
+	return 0, false
+}
+
+// packageLevel returns the level of the first override matching the package of the function,
+// or the level of the logger
+func (l *Logger) packageLevel(overrides []levelOverride, function string) int {
+	if function == "" || len(overrides) == 0 {
+		return l.level()
+	}
+	pkg := packageOf(function)
+	for _, o := range overrides {
+		if ok, _ := pathpkg.Match(o.pattern, pkg); ok {
+			return o.level
+		}
+	}
+	return l.level()
+}
+
+// lowestLevel returns the lowest level the logger can have for a record, whatever its caller
+func (l *Logger) lowestLevel() int {
+	overrides := l.levelOverrides()
+	if level, ok := l.nameLevel(overrides); ok {
+		return level
+	}
+	min := l.level()
+	for _, o := range overrides {
+		if o.level < min {
+			min = o.level
+		}
+	}
+	return min
+}
+
+// packageOf returns the package path of a function name as reported by the runtime,
+// e.g. "github.com/acme/app/store" for "github.com/acme/app/store.(*DB).Get"
+func packageOf(function string) string {
+	dir, name := "", function
+	if i := strings.LastIndex(function, "/"); i >= 0 {
+		dir, name = function[:i+1], function[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[:i]
+	}
+	return dir + name
+}
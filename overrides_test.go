@@ -0,0 +1,132 @@
+package noodlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b)
+
+	l.Named("db").Named("pool").Info("connection opened")
+	if expected := `"logger":"db.pool"`; !strings.Contains(b.String(), expected) {
+		t.Errorf(errorFmt, "TestNamed", expected, b.String())
+	}
+
+	b.Reset()
+	l.Info("no name")
+	if strings.Contains(b.String(), loggerKey) {
+		t.Errorf(errorFmt, "TestNamed", "no logger field", b.String())
+	}
+}
+
+func TestNameLevelOverrides(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).SetLevelOverrides(map[string]string{"db": "trace", "http*": "warn", "db.cache": "error"})
+
+	testMap := map[*Logger]string{
+		l:                            "info",
+		l.Named("db"):                "trace",
+		l.Named("db").Named("sql"):   "trace",
+		l.Named("db").Named("cache"): "error",
+		l.Named("http"):              "warn",
+		l.Named("https"):             "warn",
+		l.Named("other"):             "info",
+	}
+
+	for logger, expected := range testMap {
+		b.Reset()
+		logger.Trace("trace")
+		logger.Debug("debug")
+		logger.Info("info")
+		logger.Warn("warn")
+		logger.Error("error")
+		if first := strings.SplitN(b.String(), "\n", 2)[0]; !strings.Contains(first, `"message":"`+expected+`"`) {
+			t.Errorf(errorFmt, "TestNameLevelOverrides", expected, first)
+		}
+	}
+}
+
+func TestLevelOverridesAfterNamed(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b)
+	db := l.Named("db")
+
+	l.SetLevelOverrides(map[string]string{"db": "debug"})
+	db.Debug("after overrides")
+	if !strings.Contains(b.String(), `"message":"after overrides"`) {
+		t.Errorf(errorFmt, "TestLevelOverridesAfterNamed", "after overrides", b.String())
+	}
+
+	b.Reset()
+	l.SetLevel(WarnLevel)
+	db.Info("named")
+	l.Named("http").Info("other")
+	if !strings.Contains(b.String(), `"message":"named"`) || strings.Contains(b.String(), `"message":"other"`) {
+		t.Errorf(errorFmt, "TestLevelOverridesAfterNamed level", "named only", b.String())
+	}
+
+	b.Reset()
+	db.SetLevelOverrides(map[string]string{"db": "error"}).SetLevel(TraceLevel)
+	l.Debug("parent")
+	l.Named("db").Trace("parent db")
+	if b.Len() != 0 {
+		t.Errorf(errorFmt, "TestLevelOverridesAfterNamed child", "", b.String())
+	}
+}
+
+func TestPackageLevelOverrides(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).SetConfigs(Configs{
+		LevelOverrides: map[string]string{"github.com/gyozatech/*": "trace"},
+	})
+
+	l.Debug("debug")
+	l.Debugf("debugf")
+	l.Debugw("debugw")
+	l.DebugCtx(nil, "debug ctx")
+	for _, expected := range []string{"debug", "debugf", "debugw", "debug ctx"} {
+		if !strings.Contains(b.String(), `"message":"`+expected+`"`) {
+			t.Errorf(errorFmt, "TestPackageLevelOverrides", expected, b.String())
+		}
+	}
+
+	b.Reset()
+	l.SetLevelOverrides(map[string]string{"github.com/other/*": "trace"})
+	l.Debug("debug")
+	if b.Len() != 0 {
+		t.Errorf(errorFmt, "TestPackageLevelOverrides", "", b.String())
+	}
+	if !l.Enabled(TraceLevel) {
+		t.Errorf(errorFmt, "TestPackageLevelOverrides", "trace enabled by an override", false)
+	}
+}
+
+func TestParseLevelOverrides(t *testing.T) {
+	overrides, err := ParseLevelOverrides(" db=trace, http = warn,,")
+	if err != nil || len(overrides) != 2 || overrides["db"] != "trace" || overrides["http"] != "warn" {
+		t.Errorf(errorFmt, "TestParseLevelOverrides", "map[db:trace http:warn]", overrides)
+	}
+
+	for _, spec := range []string{"db", "db=verbose", "=trace", "[=trace"} {
+		if _, err := ParseLevelOverrides(spec); err == nil {
+			t.Errorf(errorFmt, "TestParseLevelOverrides "+spec, "an error", nil)
+		}
+	}
+}
+
+func TestPackageOf(t *testing.T) {
+	testMap := map[string]string{
+		"github.com/acme/app/store.(*DB).Get": "github.com/acme/app/store",
+		"github.com/acme/app.main.func1":      "github.com/acme/app",
+		"main.main":                           "main",
+	}
+
+	for function, expected := range testMap {
+		if actual := packageOf(function); actual != expected {
+			t.Errorf(errorFmt, "TestPackageOf", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package noodlog
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	gcpPreset     = "gcp"
+	ecsPreset     = "ecs"
+	datadogPreset = "datadog"
+
+	// ecsVersion is the version of the Elastic Common Schema followed by the ecs preset
+	ecsVersion = "1.6.0"
+)
+
+// traceIDKeys and spanIDKeys are the field keys renamed by the presets into the trace and span keys of the backend
+var (
+	traceIDKeys = map[string]bool{"trace": true, "trace_id": true, "traceId": true}
+	spanIDKeys  = map[string]bool{"span_id": true, "spanId": true}
+)
+
+// presetEncoder encodes the records as JSON objects parsed natively by a logging backend
+type presetEncoder struct {
+	name string
+}
+
+// presets maps the preset names to their encoders
+var presets = map[string]presetEncoder{
+	gcpPreset:     {name: gcpPreset},
+	ecsPreset:     {name: ecsPreset},
+	datadogPreset: {name: datadogPreset},
+}
+
+// Preset function let you emit records parsed natively by a logging backend: "gcp" (Google Cloud Logging),
+// "ecs" (Elastic Common Schema) or "datadog". The caller tracing is enabled to fill the source location of the records.
+// Unknown presets are ignored.
+func (l *Logger) Preset(preset string) *Logger {
+	return l.update(func(s *settings) {
+		s.setPreset(preset)
+	})
+}
+
+// setPreset selects the encoder of a preset and enables the caller tracing
+func (s *settings) setPreset(preset string) {
+	encoder, ok := presets[strings.ToLower(preset)]
+	if !ok {
+		return
+	}
+	s.encoder = encoder
+	s.traceCaller = true
+}
+
+// Encode marshals the entry as a JSON object with the keys of the preset backend
+func (e presetEncoder) Encode(entry Entry) ([]byte, error) {
+	var fields []Field
+	switch e.name {
+	case gcpPreset:
+		fields = gcpFields(entry)
+	case ecsPreset:
+		fields = ecsFields(entry)
+	default:
+		fields = datadogFields(entry)
+	}
+
+	reserved := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		reserved[f.Key] = true
+	}
+	for _, f := range entry.Fields {
+		key := e.fieldKey(f.Key)
+		if !reserved[key] {
+			fields = append(fields, Field{Key: key, Value: f.Value})
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writeJSONField(&b, f)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// fieldKey renames the trace and span fields into the keys of the backend
+func (e presetEncoder) fieldKey(key string) string {
+	switch {
+	case traceIDKeys[key] && e.name == gcpPreset:
+		return "logging.googleapis.com/trace"
+	case spanIDKeys[key] && e.name == gcpPreset:
+		return "logging.googleapis.com/spanId"
+	case traceIDKeys[key] && e.name == ecsPreset:
+		return "trace.id"
+	case spanIDKeys[key] && e.name == ecsPreset:
+		return "span.id"
+	case traceIDKeys[key] && e.name == datadogPreset:
+		return "dd.trace_id"
+	case spanIDKeys[key] && e.name == datadogPreset:
+		return "dd.span_id"
+	}
+	return key
+}
+
+// gcpFields returns the keys of a Google Cloud Logging structured record
+func gcpFields(entry Entry) []Field {
+	fields := []Field{
+		{Key: "severity", Value: gcpSeverity(entry.Level)},
+		{Key: "message", Value: entry.Message},
+		{Key: "time", Value: entry.Time.Format(time.RFC3339Nano)},
+	}
+	if entry.File != "" || entry.Function != "" {
+		file, line := splitFileLine(entry.File)
+		location := map[string]interface{}{"function": entry.Function}
+		if file != "" {
+			location["file"] = file
+			location["line"] = strconv.Itoa(line)
+		}
+		fields = append(fields, Field{Key: "logging.googleapis.com/sourceLocation", Value: location})
+	}
+	return fields
+}
+
+// ecsFields returns the keys of an Elastic Common Schema record
+func ecsFields(entry Entry) []Field {
+	fields := []Field{
+		{Key: "@timestamp", Value: entry.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00")},
+		{Key: "log.level", Value: entry.Level},
+		{Key: "message", Value: toText(entry.Message)},
+		{Key: "ecs.version", Value: ecsVersion},
+	}
+	if entry.File != "" {
+		file, line := splitFileLine(entry.File)
+		fields = append(fields, Field{Key: "log.origin.file.name", Value: file}, Field{Key: "log.origin.file.line", Value: line})
+	}
+	if entry.Function != "" {
+		fields = append(fields, Field{Key: "log.origin.function", Value: entry.Function})
+	}
+	return fields
+}
+
+// datadogFields returns the keys of a record following the Datadog conventions
+func datadogFields(entry Entry) []Field {
+	fields := []Field{
+		{Key: "status", Value: datadogStatus(entry.Level)},
+		{Key: "message", Value: entry.Message},
+		{Key: "timestamp", Value: entry.Time.Format(time.RFC3339Nano)},
+	}
+	if entry.File != "" {
+		fields = append(fields, Field{Key: "logger.file_name", Value: entry.File})
+	}
+	if entry.Function != "" {
+		fields = append(fields, Field{Key: "logger.method_name", Value: entry.Function})
+	}
+	return fields
+}
+
+// gcpSeverity converts a level label into a Google Cloud Logging severity, the custom levels
+// are converted as the nearest built-in level below them
+func gcpSeverity(label string) string {
+	switch level := levelOf(label); {
+	case level < infoLevel:
+		return "DEBUG"
+	case level < warnLevel:
+		return "INFO"
+	case level < errorLevel:
+		return "WARNING"
+	case level < panicLevel:
+		return "ERROR"
+	case level < fatalLevel:
+		return "CRITICAL"
+	default:
+		return "ALERT"
+	}
+}
+
+// datadogStatus converts a level label into a Datadog status, the custom levels
+// are converted as the nearest built-in level below them
+func datadogStatus(label string) string {
+	switch level := levelOf(label); {
+	case level < infoLevel:
+		return debugLabel
+	case level < warnLevel:
+		return infoLabel
+	case level < errorLevel:
+		return warnLabel
+	case level < panicLevel:
+		return errorLabel
+	case level < fatalLevel:
+		return "critical"
+	default:
+		return "emergency"
+	}
+}
+
+// splitFileLine splits the file traced by the logger into its path and its line number
+func splitFileLine(file string) (string, int) {
+	i := strings.LastIndex(file, ":")
+	if i < 0 {
+		return file, 0
+	}
+	line, err := strconv.Atoi(file[i+1:])
+	if err != nil {
+		return file, 0
+	}
+	return file[:i], line
+}
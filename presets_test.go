@@ -0,0 +1,43 @@
+package noodlog
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPresetEncoders(t *testing.T) {
+	entry := testEntry
+	entry.Level = warnLabel
+	entry.Fields = []Field{{"trace_id", "abc"}, {"spanId", "def"}, {"attempts", 3}}
+
+	testMap := map[string]string{
+		gcpPreset:     `{"severity":"WARNING","message":"user logged in","time":"2021-03-04T12:03:04Z","logging.googleapis.com/sourceLocation":{"file":"/home/gyoza/main.go","function":"main.main","line":"42"},"logging.googleapis.com/trace":"abc","logging.googleapis.com/spanId":"def","attempts":3}`,
+		ecsPreset:     `{"@timestamp":"2021-03-04T12:03:04.000Z","log.level":"warn","message":"user logged in","ecs.version":"1.6.0","log.origin.file.name":"/home/gyoza/main.go","log.origin.file.line":42,"log.origin.function":"main.main","trace.id":"abc","span.id":"def","attempts":3}`,
+		datadogPreset: `{"status":"warn","message":"user logged in","timestamp":"2021-03-04T12:03:04Z","logger.file_name":"/home/gyoza/main.go:42","logger.method_name":"main.main","dd.trace_id":"abc","dd.span_id":"def","attempts":3}`,
+	}
+
+	for preset, expected := range testMap {
+		actual, err := presets[preset].Encode(entry)
+		if err != nil || string(actual) != expected {
+			t.Errorf(errorFmt, "TestPresetEncoders "+preset, expected, string(actual))
+		}
+	}
+}
+
+func TestLoggerPreset(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().SetConfigs(Configs{LogWriter: &b, Preset: PresetGCP})
+
+	l.Error("boom")
+	expected := `{"severity":"ERROR","message":"boom","time":"*","logging.googleapis.com/sourceLocation":{"file":"*presets_test.go","function":"*TestLoggerPreset","line":"*"}}` + "\n"
+	if actual := b.String(); !Matches(actual, expected) {
+		t.Errorf(errorFmt, "TestLoggerPreset", expected, actual)
+	}
+	b.Reset()
+
+	l.Preset("unknown").DisableTraceCaller().Info("hello")
+	expected = `{"severity":"INFO","message":"hello","time":"*"}` + "\n"
+	if actual := b.String(); !Matches(actual, expected) {
+		t.Errorf(errorFmt, "TestLoggerPreset", expected, actual)
+	}
+}
@@ -0,0 +1,105 @@
+package noodlog
+
+import (
+	"context"
+	"fmt"
+)
+
+// Tracef function prints a log with trace log level, formatting the message with fmt.Sprintf
+func (l *Logger) Tracef(format string, args ...interface{}) {
+	l.printLogf(nil, traceLabel, format, args)
+}
+
+// Debugf function prints a log with debug log level, formatting the message with fmt.Sprintf
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.printLogf(nil, debugLabel, format, args)
+}
+
+// Infof function prints a log with info log level, formatting the message with fmt.Sprintf
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.printLogf(nil, infoLabel, format, args)
+}
+
+// Warnf function prints a log with warn log level, formatting the message with fmt.Sprintf
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.printLogf(nil, warnLabel, format, args)
+}
+
+// Errorf function prints a log with error log level, formatting the message with fmt.Sprintf
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.printLogf(nil, errorLabel, format, args)
+}
+
+// Panicf function prints a log with panic log level, formatting the message with fmt.Sprintf
+func (l *Logger) Panicf(format string, args ...interface{}) {
+	l.mu.RLock()
+	logRecord := l.composeLog(nil, panicLabel, fmt.Sprintf(format, redactArgs(args)...), nil)
+	l.mu.RUnlock()
+	panic(logRecord)
+}
+
+// Fatalf function prints a log with fatal log level, formatting the message with fmt.Sprintf
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.printLogf(nil, fatalLabel, format, args)
+	l.exit()
+}
+
+// Tracew function prints a log with trace log level and the given key-value pairs as fields of the record.
+// Keys and values alternate as in With: Tracew("message", "key1", value1, "key2", value2).
+func (l *Logger) Tracew(message string, keysAndValues ...interface{}) {
+	l.printLogw(nil, traceLabel, message, keysAndValues)
+}
+
+// Debugw function prints a log with debug log level and the given key-value pairs as fields of the record
+func (l *Logger) Debugw(message string, keysAndValues ...interface{}) {
+	l.printLogw(nil, debugLabel, message, keysAndValues)
+}
+
+// Infow function prints a log with info log level and the given key-value pairs as fields of the record
+func (l *Logger) Infow(message string, keysAndValues ...interface{}) {
+	l.printLogw(nil, infoLabel, message, keysAndValues)
+}
+
+// Warnw function prints a log with warn log level and the given key-value pairs as fields of the record
+func (l *Logger) Warnw(message string, keysAndValues ...interface{}) {
+	l.printLogw(nil, warnLabel, message, keysAndValues)
+}
+
+// Errorw function prints a log with error log level and the given key-value pairs as fields of the record
+func (l *Logger) Errorw(message string, keysAndValues ...interface{}) {
+	l.printLogw(nil, errorLabel, message, keysAndValues)
+}
+
+// Panicw function prints a log with panic log level and the given key-value pairs as fields of the record
+func (l *Logger) Panicw(message string, keysAndValues ...interface{}) {
+	l.mu.RLock()
+	logRecord := l.composeLog(nil, panicLabel, message, fieldsFromKeyValues(keysAndValues))
+	l.mu.RUnlock()
+	panic(logRecord)
+}
+
+// Fatalw function prints a log with fatal log level and the given key-value pairs as fields of the record
+func (l *Logger) Fatalw(message string, keysAndValues ...interface{}) {
+	l.printLogw(nil, fatalLabel, message, keysAndValues)
+	l.exit()
+}
+
+// printLogf prints a record whose message is formatted with fmt.Sprintf, without guessing the formatting intent
+func (l *Logger) printLogf(ctx context.Context, label string, format string, args []interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if level := l.callerLevel(); l.isEnabledAt(label, level) {
+		l.writeEntry(l.composeEntry(ctx, label, fmt.Sprintf(format, redactArgs(args)...), nil), level)
+	}
+}
+
+// printLogw prints a record with a plain text message and the given key-value pairs as fields
+func (l *Logger) printLogw(ctx context.Context, label string, message string, keysAndValues []interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if level := l.callerLevel(); l.isEnabledAt(label, level) {
+		l.writeEntry(l.composeEntry(ctx, label, message, fieldsFromKeyValues(keysAndValues)), level)
+	}
+}
@@ -0,0 +1,73 @@
+package noodlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFormattedMethods(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).Level(traceLabel).EnableTraceCaller()
+
+	methods := map[string]func(string, ...interface{}){
+		traceLabel: l.Tracef,
+		debugLabel: l.Debugf,
+		infoLabel:  l.Infof,
+		warnLabel:  l.Warnf,
+		errorLabel: l.Errorf,
+	}
+	for label, method := range methods {
+		method("%d%% done for %s", 100, "gyoza")
+		expected := `{"level":"` + label + `","file":"*printf_test.go:*","function":"*TestFormattedMethods","message":"100% done for gyoza","time":"*"}`
+		if actual := b.String(); !Matches(actual, expected) {
+			t.Errorf(errorFmt, "TestFormattedMethods "+label, expected, actual)
+		}
+		b.Reset()
+	}
+
+	l.Infof("100%")
+	if actual := b.String(); !strings.Contains(actual, `"message":"100%!(NOVERB)"`) {
+		t.Errorf(errorFmt, "TestFormattedMethods", "fmt.Sprintf output", actual)
+	}
+}
+
+func TestKeyValueMethods(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).Level(traceLabel).With("service", "noodles", "attempt", 0)
+
+	methods := map[string]func(string, ...interface{}){
+		traceLabel: l.Tracew,
+		debugLabel: l.Debugw,
+		infoLabel:  l.Infow,
+		warnLabel:  l.Warnw,
+		errorLabel: l.Errorw,
+	}
+	for label, method := range methods {
+		method("100% done", "attempt", 2, "user", "gyoza", "orphan")
+		expected := `{"level":"` + label + `","message":"100% done","time":"*","service":"noodles","attempt":2,"user":"gyoza","!BADKEY":"orphan"}`
+		if actual := b.String(); !Matches(actual, expected) {
+			t.Errorf(errorFmt, "TestKeyValueMethods "+label, expected, actual)
+		}
+		b.Reset()
+	}
+}
+
+func TestPanicfAndPanicw(t *testing.T) {
+	l := NewLogger()
+	testMap := map[string]func(){
+		`"message":"100% of 3"`:              func() { l.Panicf("100%% of %d", 3) },
+		`"message":"boom","time":*,"code":7`: func() { l.Panicw("boom", "code", 7) },
+	}
+
+	for expected, panicking := range testMap {
+		func() {
+			defer func() {
+				if actual, _ := recover().(string); !Matches(actual, "*"+expected+"*") {
+					t.Errorf(errorFmt, "TestPanicfAndPanicw", expected, actual)
+				}
+			}()
+			panicking()
+		}()
+	}
+}
@@ -0,0 +1,171 @@
+package noodlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// paramRedactor obscures the values of the sensitive keys found in a JSON tree
+type paramRedactor struct {
+	keys       map[string]bool
+	rules      []rule
+	redactions map[string]Redaction
+	// defaultRedaction is applied to the params without a redaction, nil means the fixed mask
+	defaultRedaction Redaction
+}
+
+// redactors caches the redactors by their sensitive params, so that they're built once
+var redactors sync.Map
+
+// getRedactor returns the cached redactor for the given sensitive params
+func getRedactor(sensitiveParams []string) *paramRedactor {
+	cacheKey := strings.Join(sensitiveParams, "\x00")
+	if r, ok := redactors.Load(cacheKey); ok {
+		return r.(*paramRedactor)
+	}
+
+	r := &paramRedactor{keys: make(map[string]bool, len(sensitiveParams))}
+	for _, param := range sensitiveParams {
+		rl, err := parseRule(param)
+		if err != nil || rl.isPlainKey() {
+			r.keys[param] = true
+			continue
+		}
+		r.rules = append(r.rules, rl)
+	}
+	actual, _ := redactors.LoadOrStore(cacheKey, r)
+	return actual.(*paramRedactor)
+}
+
+// withRedactions returns a copy of the redactor applying the given redactions, keyed by sensitive param,
+// and the default redaction to the other params
+func (r *paramRedactor) withRedactions(redactions map[string]Redaction, defaultRedaction Redaction) *paramRedactor {
+	if len(redactions) == 0 && defaultRedaction == nil {
+		return r
+	}
+	c := *r
+	c.redactions = redactions
+	c.defaultRedaction = defaultRedaction
+	return &c
+}
+
+// sensitiveParam returns the sensitive param matching the given path, if any
+func (r *paramRedactor) sensitiveParam(p path) (string, bool) {
+	if len(p) == 0 {
+		return "", false
+	}
+	if last := p[len(p)-1]; !last.isIndex && r.keys[last.key] {
+		return last.key, true
+	}
+	for _, rl := range r.rules {
+		if rl.matches(p) {
+			return rl.param, true
+		}
+	}
+	return "", false
+}
+
+// redactSensitive applies the redaction of the param to a sensitive value, false means the key has to be removed
+func (r *paramRedactor) redactSensitive(param string, value interface{}) (interface{}, bool) {
+	if redaction, ok := r.redactions[param]; ok && redaction != nil {
+		return redaction.Redact(value)
+	}
+	if r.defaultRedaction != nil {
+		return r.defaultRedaction.Redact(value)
+	}
+	return obscuredValue, true
+}
+
+// redact returns a copy of value with the values of the sensitive keys obscured, whatever their type.
+// Structs, maps and slices are converted into their JSON tree, so that they're redacted as the equivalent JSON strings.
+func (r *paramRedactor) redact(value interface{}) interface{} {
+	return r.redactAt(nil, value)
+}
+
+// redactField returns the value of a field, obscured when its key is sensitive; false means the field has to be removed
+func (r *paramRedactor) redactField(f Field) (interface{}, bool) {
+	p := path{{key: f.Key}}
+	if param, ok := r.sensitiveParam(p); ok {
+		return r.redactSensitive(param, f.Value)
+	}
+	return r.redactAt(p, f.Value), true
+}
+
+// redactAt redacts a value found at the given path of the tree
+func (r *paramRedactor) redactAt(p path, value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, float64, json.Number:
+		return v
+	case string:
+		return r.redactJSONString(p, v)
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			child := p.child(pathElem{key: key})
+			if param, ok := r.sensitiveParam(child); ok {
+				if val, keep := r.redactSensitive(param, val); keep {
+					redacted[key] = val
+				}
+			} else {
+				redacted[key] = r.redactAt(child, val)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, val := range v {
+			child := p.child(pathElem{index: i, isIndex: true})
+			if param, ok := r.sensitiveParam(child); ok {
+				redacted[i] = obscuredValue
+				if val, keep := r.redactSensitive(param, val); keep {
+					redacted[i] = val
+				}
+			} else {
+				redacted[i] = r.redactAt(child, val)
+			}
+		}
+		return redacted
+	}
+
+	tree, ok := toJSONTree(value)
+	if !ok {
+		return value
+	}
+	return r.redactAt(p, tree)
+}
+
+// redactJSONString redacts a string containing a JSON object or array, leaving the other strings untouched
+func (r *paramRedactor) redactJSONString(p path, s string) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') || !json.Valid([]byte(trimmed)) {
+		return s
+	}
+	tree, _ := decodeJSON([]byte(trimmed))
+	jsn, err := json.Marshal(r.redactAt(p, tree))
+	if err != nil {
+		return s
+	}
+	return string(jsn)
+}
+
+// toJSONTree converts a value into the tree of maps, slices and leaves of its JSON representation
+func toJSONTree(value interface{}) (interface{}, bool) {
+	jsn, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	return decodeJSON(jsn)
+}
+
+// decodeJSON decodes a JSON document keeping the numbers as json.Number, so that they're not altered
+func decodeJSON(jsn []byte) (interface{}, bool) {
+	var tree interface{}
+	d := json.NewDecoder(bytes.NewReader(jsn))
+	d.UseNumber()
+	if err := d.Decode(&tree); err != nil {
+		return nil, false
+	}
+	return tree, true
+}
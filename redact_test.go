@@ -0,0 +1,89 @@
+package noodlog
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func redactToJSON(r *paramRedactor, value interface{}) string {
+	jsn, _ := json.Marshal(r.redact(value))
+	return string(jsn)
+}
+
+func TestRedactAnyValueType(t *testing.T) {
+	r := getRedactor([]string{"secret"})
+	input := `{"a": {"secret": 42}, "b": [{"secret": true}, {"secret": null}], "c": {"secret": {"nested": "x"}}, "d": 12345678901234567890}`
+	expected := `{"a":{"secret":"**********"},"b":[{"secret":"**********"},{"secret":"**********"}],"c":{"secret":"**********"},"d":12345678901234567890}`
+
+	if actual := redactToJSON(r, strToObj(input)); actual != expected {
+		t.Errorf(errorFmt, "TestRedactAnyValueType", expected, actual)
+	}
+}
+
+func TestRedactExactKeys(t *testing.T) {
+	r := getRedactor([]string{"password"})
+	input := `{"password_hint": "my \"password\": none", "user": "gyoza", "password": "a\"b", "note": "password"}`
+	expected := `{"note":"password","password":"**********","password_hint":"my \"password\": none","user":"gyoza"}`
+
+	if actual := redactToJSON(r, strToObj(input)); actual != expected {
+		t.Errorf(errorFmt, "TestRedactExactKeys", expected, actual)
+	}
+}
+
+type credentials struct {
+	User     string            `json:"user"`
+	Password string            `json:"password"`
+	Tokens   map[string]string `json:"tokens"`
+}
+
+func TestRedactIdenticalResults(t *testing.T) {
+	r := getRedactor([]string{"password", "refresh"})
+	expected := `{"password":"**********","tokens":{"access":"abc","refresh":"**********"},"user":"gyoza"}`
+
+	inputs := map[string]interface{}{
+		"string": `{"user": "gyoza", "password": "Sup3rS3cr3t", "tokens": {"access": "abc", "refresh": "def"}}`,
+		"struct": credentials{"gyoza", "Sup3rS3cr3t", map[string]string{"access": "abc", "refresh": "def"}},
+		"map":    map[string]interface{}{"user": "gyoza", "password": "Sup3rS3cr3t", "tokens": map[string]string{"access": "abc", "refresh": "def"}},
+	}
+	for name, input := range inputs {
+		if s, ok := input.(string); ok {
+			input = strToObj(s)
+		}
+		if actual := redactToJSON(r, input); actual != expected {
+			t.Errorf(errorFmt, "TestRedactIdenticalResults "+name, expected, actual)
+		}
+	}
+}
+
+func TestRedactDoesNotModifyInput(t *testing.T) {
+	input := map[string]interface{}{"password": "Sup3rS3cr3t"}
+	getRedactor([]string{"password"}).redact(input)
+
+	if input["password"] != "Sup3rS3cr3t" {
+		t.Errorf(errorFmt, "TestRedactDoesNotModifyInput", "Sup3rS3cr3t", input["password"])
+	}
+}
+
+func TestRedactJSONString(t *testing.T) {
+	r := getRedactor([]string{"password"})
+	testMap := map[string]string{
+		`{"password": "x"}`:   `{"password":"**********"}`,
+		`[{"password": 1}]`:   `[{"password":"**********"}]`,
+		`not a json {`:        `not a json {`,
+		`{"password": broken`: `{"password": broken`,
+	}
+	for input, expected := range testMap {
+		if actual := r.redactJSONString(nil, input); actual != expected {
+			t.Errorf(errorFmt, "TestRedactJSONString", expected, actual)
+		}
+	}
+}
+
+func TestGetRedactorIsCached(t *testing.T) {
+	if getRedactor([]string{"a", "b"}) != getRedactor([]string{"a", "b"}) {
+		t.Errorf(errorFmt, "TestGetRedactorIsCached", "same redactor", "different redactors")
+	}
+	if getRedactor([]string{"a", "b"}) == getRedactor([]string{"ab"}) {
+		t.Errorf(errorFmt, "TestGetRedactorIsCached", "different redactors", "same redactor")
+	}
+}
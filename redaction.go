@@ -0,0 +1,123 @@
+package noodlog
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Redaction interface tells how the value of a sensitive param is obscured.
+// Redact returns the value to be logged in place of the original one, or false to remove the key.
+type Redaction interface {
+	Redact(value interface{}) (interface{}, bool)
+}
+
+// maskRedaction replaces the value with a fixed mask
+type maskRedaction struct{}
+
+// revealLastRedaction masks every character but the last ones
+type revealLastRedaction struct {
+	visible int
+}
+
+// hashRedaction replaces the value with its salted hash
+type hashRedaction struct {
+	salt string
+}
+
+// lengthMaskRedaction replaces every character of the value with an asterisk
+type lengthMaskRedaction struct{}
+
+// removeRedaction removes the key from the record
+type removeRedaction struct{}
+
+// Mask returns the default Redaction, replacing the value with "**********"
+func Mask() Redaction {
+	return maskRedaction{}
+}
+
+// RevealLast returns a Redaction masking every character of the value but the last n, e.g. the last 4 digits of a card.
+// Values not longer than n are fully masked.
+func RevealLast(n int) Redaction {
+	return revealLastRedaction{visible: n}
+}
+
+// Hash returns a Redaction replacing the value with its HMAC-SHA256 keyed by salt, so that
+// the same value can be correlated across records without being revealed
+func Hash(salt string) Redaction {
+	return hashRedaction{salt: salt}
+}
+
+// MaskPreservingLength returns a Redaction replacing every character of the value with an asterisk
+func MaskPreservingLength() Redaction {
+	return lengthMaskRedaction{}
+}
+
+// Remove returns a Redaction removing the key from the record. Array elements are masked instead.
+func Remove() Redaction {
+	return removeRedaction{}
+}
+
+// Redact replaces the value with the fixed mask
+func (r maskRedaction) Redact(value interface{}) (interface{}, bool) {
+	return obscuredValue, true
+}
+
+// Redact masks every character but the last ones
+func (r revealLastRedaction) Redact(value interface{}) (interface{}, bool) {
+	text := []rune(toText(value))
+	masked := len(text) - r.visible
+	if masked <= 0 || r.visible <= 0 {
+		masked = len(text)
+	}
+	return strings.Repeat("*", masked) + string(text[masked:]), true
+}
+
+// Redact replaces the value with the hex encoded HMAC-SHA256 of its text
+func (r hashRedaction) Redact(value interface{}) (interface{}, bool) {
+	mac := hmac.New(sha256.New, []byte(r.salt))
+	mac.Write([]byte(toText(value)))
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil)), true
+}
+
+// Redact replaces every character with an asterisk
+func (r lengthMaskRedaction) Redact(value interface{}) (interface{}, bool) {
+	return strings.Repeat("*", len([]rune(toText(value)))), true
+}
+
+// Redact removes the key
+func (r removeRedaction) Redact(value interface{}) (interface{}, bool) {
+	return nil, false
+}
+
+// SetRedaction function let you choose how the values of a sensitive param are obscured; the params without a redaction are masked
+func (l *Logger) SetRedaction(param string, redaction Redaction) *Logger {
+	return l.update(func(s *settings) {
+		s.setRedactions(map[string]Redaction{param: redaction})
+	})
+}
+
+// SetDefaultRedaction function let you choose how the values of the sensitive params without a redaction are obscured,
+// nil restores the default mask
+func (l *Logger) SetDefaultRedaction(redaction Redaction) *Logger {
+	return l.update(func(s *settings) {
+		s.defaultRedaction = redaction
+	})
+}
+
+// setRedactions adds the given redactions to the current ones, a nil redaction restores the default mask
+func (s *settings) setRedactions(redactions map[string]Redaction) {
+	merged := make(map[string]Redaction, len(s.redactions)+len(redactions))
+	for param, redaction := range s.redactions {
+		merged[param] = redaction
+	}
+	for param, redaction := range redactions {
+		if redaction == nil {
+			delete(merged, param)
+		} else {
+			merged[param] = redaction
+		}
+	}
+	s.redactions = merged
+}
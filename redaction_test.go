@@ -0,0 +1,79 @@
+package noodlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedactions(t *testing.T) {
+	testMap := []struct {
+		redaction Redaction
+		value     interface{}
+		expected  interface{}
+		keep      bool
+	}{
+		{Mask(), 42, obscuredValue, true},
+		{RevealLast(4), "4111111111111111", "************1111", true},
+		{RevealLast(4), "abc", "***", true},
+		{RevealLast(0), "abc", "***", true},
+		{MaskPreservingLength(), "Sup3rS3cr3t", "***********", true},
+		{MaskPreservingLength(), "äöü", "***", true},
+		{Remove(), "anything", nil, false},
+	}
+
+	for _, test := range testMap {
+		actual, keep := test.redaction.Redact(test.value)
+		if actual != test.expected || keep != test.keep {
+			t.Errorf(errorFmt, "TestRedactions", test.expected, actual)
+		}
+	}
+}
+
+func TestHashIsDeterministic(t *testing.T) {
+	h1, _ := Hash("salt").Redact("user-1")
+	h2, _ := Hash("salt").Redact("user-1")
+	h3, _ := Hash("pepper").Redact("user-1")
+	h4, _ := Hash("salt").Redact("user-2")
+
+	if s, _ := h1.(string); !strings.HasPrefix(s, "sha256:") || len(s) != len("sha256:")+64 {
+		t.Errorf(errorFmt, "TestHashIsDeterministic", "sha256 hex digest", h1)
+	}
+	if h1 != h2 || h1 == h3 || h1 == h4 {
+		t.Errorf(errorFmt, "TestHashIsDeterministic", "same hash only for same salt and value", []interface{}{h1, h2, h3, h4})
+	}
+}
+
+func TestLoggerRedactions(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).SetConfigs(Configs{
+		ObscureSensitiveData: Enable,
+		SensitiveParams:      []string{"password", "cardNumber", "userId", "ssn", "items[*]"},
+		Redactions: map[string]Redaction{
+			"cardNumber": RevealLast(4),
+			"ssn":        MaskPreservingLength(),
+			"password":   Remove(),
+			"items[*]":   Remove(),
+		},
+	}).SetRedaction("userId", Hash("salt")).With("password", "p", "userId", "u1")
+
+	l.Info(`{"password": "p", "cardNumber": "4111111111111111", "ssn": "123-45-6789", "items": [1, 2]}`)
+
+	actual := b.String()
+	for _, expected := range []string{
+		`"message":{"cardNumber":"************1111","items":["**********","**********"],"ssn":"***********"}`,
+		`"userId":"sha256:`,
+	} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf(errorFmt, "TestLoggerRedactions", expected, actual)
+		}
+	}
+	if strings.Contains(actual, "password") {
+		t.Errorf(errorFmt, "TestLoggerRedactions", "password removed", actual)
+	}
+
+	l.SetRedaction("ssn", nil)
+	if _, ok := l.redactions["ssn"]; ok {
+		t.Errorf(errorFmt, "TestLoggerRedactions", "ssn redaction removed", l.redactions)
+	}
+}
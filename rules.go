@@ -0,0 +1,146 @@
+package noodlog
+
+import (
+	"fmt"
+	pathpkg "path"
+	"strconv"
+	"strings"
+)
+
+// caseInsensitivePrefix marks a sensitive param rule matching the keys regardless of their case
+const caseInsensitivePrefix = "(?i)"
+
+// path is the position of a value in a JSON tree, from the root
+type path []pathElem
+
+// pathElem is either an object key or an array index
+type pathElem struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// child returns a new path made of p followed by elem
+func (p path) child(elem pathElem) path {
+	return append(p[:len(p):len(p)], elem)
+}
+
+// rule is a parsed sensitive param:
+//   - "password" matches the key at any depth
+//   - "*_secret" matches the keys at any depth with a glob pattern
+//   - "user.credentials.token" matches the path from the root
+//   - "items[*].cardNumber" matches any element of an array, "items[0]" a single one
+//   - "(?i)password" matches regardless of the case
+type rule struct {
+	param           string
+	segments        []segment
+	caseInsensitive bool
+}
+
+// segment is an element of a rule, matching either a key or an array index
+type segment struct {
+	pattern  string
+	glob     bool
+	isIndex  bool
+	anyIndex bool
+	index    int
+}
+
+// parseRule parses a sensitive param, reporting the malformed ones
+func parseRule(param string) (rule, error) {
+	r := rule{param: param}
+	if strings.HasPrefix(param, caseInsensitivePrefix) {
+		r.caseInsensitive = true
+		param = strings.TrimPrefix(param, caseInsensitivePrefix)
+	}
+	if param == "" {
+		return r, fmt.Errorf("noodlog: empty sensitive param")
+	}
+
+	for _, part := range strings.Split(param, ".") {
+		key := part
+		var indexes []string
+		if i := strings.Index(part, "["); i >= 0 {
+			key = part[:i]
+			var err error
+			if indexes, err = parseIndexes(part[i:]); err != nil {
+				return r, fmt.Errorf("noodlog: invalid sensitive param %q: %w", param, err)
+			}
+		}
+		if key == "" && len(indexes) == 0 {
+			return r, fmt.Errorf("noodlog: invalid sensitive param %q: empty key", param)
+		}
+		if key != "" {
+			if _, err := pathpkg.Match(key, ""); err != nil {
+				return r, fmt.Errorf("noodlog: invalid sensitive param %q: %w", param, err)
+			}
+			if r.caseInsensitive {
+				key = strings.ToLower(key)
+			}
+			r.segments = append(r.segments, segment{pattern: key, glob: strings.ContainsAny(key, "*?")})
+		}
+		for _, index := range indexes {
+			seg := segment{isIndex: true, anyIndex: index == "*"}
+			if !seg.anyIndex {
+				seg.index, _ = strconv.Atoi(index)
+			}
+			r.segments = append(r.segments, seg)
+		}
+	}
+	return r, nil
+}
+
+// parseIndexes parses a sequence of array indexes such as [*][0]
+func parseIndexes(s string) ([]string, error) {
+	var indexes []string
+	for s != "" {
+		end := strings.Index(s, "]")
+		if s[0] != '[' || end < 0 {
+			return nil, fmt.Errorf("malformed index %q", s)
+		}
+		index := s[1:end]
+		if n, err := strconv.Atoi(index); index != "*" && (err != nil || n < 0) {
+			return nil, fmt.Errorf("malformed index %q", index)
+		}
+		indexes = append(indexes, index)
+		s = s[end+1:]
+	}
+	return indexes, nil
+}
+
+// isPlainKey tells if the rule matches an exact key at any depth
+func (r rule) isPlainKey() bool {
+	return len(r.segments) == 1 && !r.caseInsensitive && !r.segments[0].glob && !r.segments[0].isIndex
+}
+
+// matches tells if the rule matches the path: rules made of a single key match the last element
+// of the path at any depth, the other ones the whole path from the root
+func (r rule) matches(p path) bool {
+	if len(r.segments) == 1 && !r.segments[0].isIndex {
+		return r.segments[0].matches(p[len(p)-1], r.caseInsensitive)
+	}
+	if len(r.segments) != len(p) {
+		return false
+	}
+	for i, seg := range r.segments {
+		if !seg.matches(p[i], r.caseInsensitive) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s segment) matches(elem pathElem, caseInsensitive bool) bool {
+	if s.isIndex || elem.isIndex {
+		return s.isIndex && elem.isIndex && (s.anyIndex || s.index == elem.index)
+	}
+	key := elem.key
+	if caseInsensitive {
+		key = strings.ToLower(key)
+	}
+	if !s.glob {
+		return s.pattern == key
+	}
+	matched, _ := pathpkg.Match(s.pattern, key)
+	return matched
+}
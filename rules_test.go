@@ -0,0 +1,76 @@
+package noodlog
+
+import (
+	"testing"
+)
+
+func TestParseRuleErrors(t *testing.T) {
+	for _, param := range []string{"", "(?i)", "items[", "items[x]", "items[-1]", "a..b", "[a-"} {
+		if _, err := parseRule(param); err == nil {
+			t.Errorf(errorFmt, "TestParseRuleErrors "+param, "error", nil)
+		}
+	}
+	for _, param := range []string{"password", "*_secret", "user.credentials.token", "items[*].cardNumber", "matrix[0][*]", "(?i)Token"} {
+		if _, err := parseRule(param); err != nil {
+			t.Errorf(errorFmt, "TestParseRuleErrors "+param, nil, err)
+		}
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	p := func(elems ...interface{}) path {
+		var result path
+		for _, e := range elems {
+			if i, ok := e.(int); ok {
+				result = append(result, pathElem{index: i, isIndex: true})
+			} else {
+				result = append(result, pathElem{key: e.(string)})
+			}
+		}
+		return result
+	}
+
+	testMap := []struct {
+		rule     string
+		path     path
+		expected bool
+	}{
+		{"token", p("a", "b", "token"), true},
+		{"*_secret", p("client_secret"), true},
+		{"*_secret", p("a", "api_secret"), true},
+		{"*_secret", p("secret"), false},
+		{"user.credentials.token", p("user", "credentials", "token"), true},
+		{"user.credentials.token", p("token"), false},
+		{"user.credentials.token", p("admin", "user", "credentials", "token"), false},
+		{"user.*.token", p("user", "session", "token"), true},
+		{"items[*].cardNumber", p("items", 3, "cardNumber"), true},
+		{"items[*].cardNumber", p("items", "cardNumber"), false},
+		{"items[1].cardNumber", p("items", 0, "cardNumber"), false},
+		{"matrix[0][*]", p("matrix", 0, 5), true},
+		{"(?i)password", p("user", "PassWord"), true},
+		{"(?i)User.Token", p("USER", "token"), true},
+		{"password", p("Password"), false},
+	}
+
+	for _, test := range testMap {
+		r, _ := parseRule(test.rule)
+		if actual := r.matches(test.path); actual != test.expected {
+			t.Errorf(errorFmt, "TestRuleMatches "+test.rule, test.expected, actual)
+		}
+	}
+}
+
+func TestRedactRules(t *testing.T) {
+	r := getRedactor([]string{"user.credentials.token", "items[*].cardNumber", "*_secret", "(?i)password"})
+	input := `{"user": {"credentials": {"token": "t1"}}, "token": "t2", "items": [{"cardNumber": "4111"}, {"cardNumber": "5500"}], "client_secret": "s", "PASSWORD": "p"}`
+	expected := `{"PASSWORD":"**********","client_secret":"**********","items":[{"cardNumber":"**********"},{"cardNumber":"**********"}],"token":"t2","user":{"credentials":{"token":"**********"}}}`
+
+	if actual := redactToJSON(r, strToObj(input)); actual != expected {
+		t.Errorf(errorFmt, "TestRedactRules", expected, actual)
+	}
+
+	field, _ := r.redactField(Field{Key: "user", Value: map[string]interface{}{"credentials": map[string]string{"token": "t1"}}})
+	if actual := redactToJSON(getRedactor(nil), field); actual != `{"credentials":{"token":"**********"}}` {
+		t.Errorf(errorFmt, "TestRedactRules", `{"credentials":{"token":"**********"}}`, actual)
+	}
+}
@@ -0,0 +1,226 @@
+package noodlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// LevelFormat tells how the level is rendered in the records
+type LevelFormat string
+
+const (
+	// LowercaseLevels renders the level as its lowercase label: "info" (default)
+	LowercaseLevels LevelFormat = "lowercase"
+	// UppercaseLevels renders the level as its uppercase label: "INFO"
+	UppercaseLevels LevelFormat = "uppercase"
+	// NumericLevels renders the level as a number: 10 trace, 20 debug, 30 info, 40 warn, 50 error, 60 panic, 70 fatal
+	NumericLevels LevelFormat = "numeric"
+)
+
+// default names of the built-in keys
+const (
+	levelKey    = "level"
+	fileKey     = "file"
+	functionKey = "function"
+	messageKey  = "message"
+	timeKey     = "time"
+	callerKey   = "caller"
+
+	// omittedKey can be used as a key name in the Schema to remove the key from the records
+	omittedKey = "-"
+)
+
+// defaultKeyOrder is the order of the built-in keys when the Schema doesn't specify it
+var defaultKeyOrder = []string{levelKey, fileKey, functionKey, messageKey, timeKey}
+
+// Schema struct describes the built-in keys of the JSON and logfmt records.
+// The empty names keep the default ones, "-" removes the key from the records.
+type Schema struct {
+	LevelKey    string `json:"levelKey,omitempty"`
+	FileKey     string `json:"fileKey,omitempty"`
+	FunctionKey string `json:"functionKey,omitempty"`
+	MessageKey  string `json:"messageKey,omitempty"`
+	TimeKey     string `json:"timeKey,omitempty"`
+	// CallerKey nests file and function in an object under this key, e.g. "caller":{"file":"...","function":"..."}
+	CallerKey string `json:"callerKey,omitempty"`
+	// Order lists the built-in keys by their default names (level, file, function, message, time and caller,
+	// which stands for file and function when nested); the missing keys follow in the default order
+	Order []string `json:"order,omitempty"`
+	// LevelFormat tells how the level is rendered, lowercase by default
+	LevelFormat LevelFormat `json:"levelFormat,omitempty"`
+}
+
+// SetSchema function let you rename, reorder and nest the built-in keys of the records, nil restores the default schema
+func (l *Logger) SetSchema(schema *Schema) *Logger {
+	return l.update(func(s *settings) {
+		s.setSchema(schema)
+	})
+}
+
+// setSchema stores a copy of the schema, so that the caller can't modify it while logging
+func (s *settings) setSchema(schema *Schema) {
+	if schema == nil {
+		s.schema = nil
+		return
+	}
+	c := *schema
+	c.Order = append([]string(nil), schema.Order...)
+	s.schema = &c
+}
+
+// keyName returns the name of a built-in key, given its default name
+func (sc *Schema) keyName(key, defaultName string) string {
+	var name string
+	switch key {
+	case levelKey:
+		name = sc.LevelKey
+	case fileKey:
+		name = sc.FileKey
+	case functionKey:
+		name = sc.FunctionKey
+	case messageKey:
+		name = sc.MessageKey
+	case timeKey:
+		name = sc.TimeKey
+	case callerKey:
+		name = sc.CallerKey
+	}
+	if name == "" {
+		return defaultName
+	}
+	return name
+}
+
+// keyOrder returns the default names of the built-in keys in the order they're written
+func (sc *Schema) keyOrder() []string {
+	nested := sc.CallerKey != "" && sc.CallerKey != omittedKey
+	seen := map[string]bool{}
+	var order []string
+	for _, key := range append(append([]string(nil), sc.Order...), defaultKeyOrder...) {
+		key = strings.ToLower(key)
+		if nested && (key == fileKey || key == functionKey) {
+			key = callerKey
+		}
+		if key == callerKey && !nested {
+			order = append(order, fileKey, functionKey)
+		} else {
+			order = append(order, key)
+		}
+	}
+
+	keys := order[:0]
+	for _, key := range order {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// isReservedKey tells if a field key clashes with a built-in key of the record
+func (e Entry) isReservedKey(key string) bool {
+	if e.schema == nil {
+		return builtinKeys[key]
+	}
+	for _, k := range []string{levelKey, fileKey, functionKey, messageKey, timeKey, callerKey} {
+		if name := e.schema.keyName(k, k); name == key && name != omittedKey && (k != callerKey || e.schema.CallerKey != "") {
+			return true
+		}
+	}
+	return false
+}
+
+// builtinFields returns the built-in keys of the entry with their values, following its schema.
+// messageName is the default name of the message key for the encoder.
+func (e Entry) builtinFields(messageName string) []Field {
+	sc := e.schema
+	if sc == nil {
+		sc = &Schema{}
+	}
+	defaults := map[string]string{levelKey: levelKey, fileKey: fileKey, functionKey: functionKey, messageKey: messageName, timeKey: timeKey}
+
+	var fields []Field
+	add := func(key string, value interface{}) {
+		if name := sc.keyName(key, defaults[key]); name != omittedKey {
+			fields = append(fields, Field{Key: name, Value: value})
+		}
+	}
+	for _, key := range sc.keyOrder() {
+		switch key {
+		case levelKey:
+			add(key, e.renderLevel(sc.LevelFormat))
+		case fileKey:
+			if e.File != "" {
+				add(key, e.File)
+			}
+		case functionKey:
+			if e.Function != "" {
+				add(key, e.Function)
+			}
+		case messageKey:
+			if e.Message != nil {
+				add(key, e.Message)
+			}
+		case timeKey:
+			add(key, e.FormattedTime())
+		case callerKey:
+			if caller := e.caller(sc); len(caller) != 0 {
+				fields = append(fields, Field{Key: sc.CallerKey, Value: caller})
+			}
+		}
+	}
+	return fields
+}
+
+// caller returns file and function nested in the caller object
+func (e Entry) caller(sc *Schema) map[string]interface{} {
+	caller := map[string]interface{}{}
+	if name := sc.keyName(fileKey, fileKey); e.File != "" && name != omittedKey {
+		caller[name] = e.File
+	}
+	if name := sc.keyName(functionKey, functionKey); e.Function != "" && name != omittedKey {
+		caller[name] = e.Function
+	}
+	return caller
+}
+
+// renderLevel returns the level of the entry in the given format
+func (e Entry) renderLevel(format LevelFormat) interface{} {
+	switch LevelFormat(strings.ToLower(string(format))) {
+	case UppercaseLevels:
+		return strings.ToUpper(e.Level)
+	case NumericLevels:
+		return levelOf(e.Level)
+	default:
+		return e.Level
+	}
+}
+
+// encodeWithSchema marshals the entry as a JSON object following its schema
+func (e JSONEncoder) encodeWithSchema(entry Entry) ([]byte, error) {
+	fields := entry.builtinFields(messageKey)
+	for _, f := range entry.Fields {
+		if !entry.isReservedKey(f.Key) {
+			fields = append(fields, f)
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writeJSONField(&b, f)
+	}
+	b.WriteByte('}')
+
+	if !e.PrettyPrint {
+		return b.Bytes(), nil
+	}
+	var pretty bytes.Buffer
+	err := json.Indent(&pretty, b.Bytes(), "", "   ")
+	return pretty.Bytes(), err
+}
@@ -0,0 +1,62 @@
+package noodlog
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONEncoderWithSchema(t *testing.T) {
+	testMap := []struct {
+		schema   Schema
+		expected string
+	}{
+		{
+			Schema{},
+			`{"level":"info","file":"/home/gyoza/main.go:42","function":"main.main","message":"user logged in","time":"2021-03-04 12:03:04 +0000 UTC","requestId":"a1b2","attempts":3}`,
+		},
+		{
+			Schema{LevelKey: "severity", MessageKey: "msg", TimeKey: "@timestamp", CallerKey: "caller", LevelFormat: UppercaseLevels},
+			`{"severity":"INFO","caller":{"file":"/home/gyoza/main.go:42","function":"main.main"},"msg":"user logged in","@timestamp":"2021-03-04 12:03:04 +0000 UTC","requestId":"a1b2","attempts":3}`,
+		},
+		{
+			Schema{Order: []string{"time", "Message"}, FunctionKey: "-", LevelFormat: NumericLevels},
+			`{"time":"2021-03-04 12:03:04 +0000 UTC","message":"user logged in","level":30,"file":"/home/gyoza/main.go:42","requestId":"a1b2","attempts":3}`,
+		},
+		{
+			Schema{Order: []string{"caller"}, CallerKey: "src", FileKey: "path"},
+			`{"src":{"function":"main.main","path":"/home/gyoza/main.go:42"},"level":"info","message":"user logged in","time":"2021-03-04 12:03:04 +0000 UTC","requestId":"a1b2","attempts":3}`,
+		},
+	}
+
+	for _, test := range testMap {
+		entry := testEntry
+		schema := test.schema
+		entry.schema = &schema
+		actual, err := JSONEncoder{}.Encode(entry)
+		if err != nil || string(actual) != test.expected {
+			t.Errorf(errorFmt, "TestJSONEncoderWithSchema", test.expected, string(actual))
+		}
+	}
+}
+
+func TestLoggerSchema(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().SetConfigs(Configs{
+		LogWriter:  &b,
+		TimeFormat: TimeFormatUnix,
+		Schema:     &Schema{LevelKey: "severity", MessageKey: "msg", TimeKey: "@timestamp"},
+	}).With("severity", "overridden", "message", "kept")
+
+	l.Warn("hello")
+	expected := `{"severity":"warn","msg":"hello","@timestamp":*,"message":"kept"}` + "\n"
+	if actual := b.String(); !Matches(actual, expected) {
+		t.Errorf(errorFmt, "TestLoggerSchema", expected, actual)
+	}
+	b.Reset()
+
+	l.Format(logfmtFormat).Warn("hello")
+	expected = "severity=warn msg=hello @timestamp=* message=kept\n"
+	if actual := b.String(); !Matches(actual, expected) {
+		t.Errorf(errorFmt, "TestLoggerSchema", expected, actual)
+	}
+}
@@ -0,0 +1,236 @@
+package noodlog
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Detector recognizes a kind of secret in the text of the records.
+// When Pattern has a capturing group only the first group is masked, otherwise the whole match.
+// Validate, when set, discards the matches which are not real secrets (e.g. failing a checksum).
+type Detector struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	Validate func(match string) bool
+}
+
+// builtinDetectors are applied by every logger with the secret detection enabled
+var builtinDetectors = []Detector{
+	{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}(?:\.[A-Za-z0-9_-]+){0,2}`)},
+	{Name: "bearer", Pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`)},
+	{Name: "aws_access_key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}\b`)},
+	{Name: "credit_card", Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), Validate: luhnValid},
+	{Name: "email", Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+	{Name: "iban", Pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`), Validate: ibanValid},
+}
+
+// secretCounters counts the secrets masked by a logger and by the loggers derived from it
+type secretCounters struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+func (c *secretCounters) add(name string, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = map[string]uint64{}
+	}
+	c.counts[name] += uint64(n)
+}
+
+func (c *secretCounters) snapshot() map[string]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[string]uint64, len(c.counts))
+	for name, n := range c.counts {
+		counts[name] = n
+	}
+	return counts
+}
+
+// EnableSecretDetection function enables the masking of JWTs, bearer tokens, AWS access keys, credit card numbers,
+// emails, IBANs and of the custom detectors in the text of messages and fields
+func (l *Logger) EnableSecretDetection() *Logger {
+	return l.update(func(s *settings) {
+		s.setSecretDetection(true)
+	})
+}
+
+// DisableSecretDetection function disables the masking of the secrets found in the text of the records
+func (l *Logger) DisableSecretDetection() *Logger {
+	return l.update(func(s *settings) {
+		s.setSecretDetection(false)
+	})
+}
+
+// AddDetector function adds a custom detector masking the matches of pattern, and enables the secret detection
+func (l *Logger) AddDetector(name string, pattern *regexp.Regexp) *Logger {
+	return l.update(func(s *settings) {
+		s.setDetectors(append(append([]Detector(nil), s.detectors...), Detector{Name: name, Pattern: pattern}))
+		s.setSecretDetection(true)
+	})
+}
+
+// RedactedSecrets returns the number of secrets masked so far by every detector, keyed by the detector name
+func (l *Logger) RedactedSecrets() map[string]uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.secretCounters == nil {
+		return map[string]uint64{}
+	}
+	return l.secretCounters.snapshot()
+}
+
+// setSecretDetection enables the secret detection, creating the counters shared with the derived loggers
+func (s *settings) setSecretDetection(enabled bool) {
+	s.detectSecrets = enabled
+	if enabled && s.secretCounters == nil {
+		s.secretCounters = &secretCounters{}
+	}
+}
+
+// setDetectors stores a copy of the custom detectors, ignoring the ones without a pattern
+func (s *settings) setDetectors(detectors []Detector) {
+	s.detectors = make([]Detector, 0, len(detectors))
+	for _, d := range detectors {
+		if d.Pattern != nil {
+			s.detectors = append(s.detectors, d)
+		}
+	}
+}
+
+// maskSecrets returns a copy of the entry with the secrets found in message and fields masked
+func (s *settings) maskSecrets(entry Entry) Entry {
+	if !s.detectSecrets {
+		return entry
+	}
+	entry.Message = s.maskSecretsIn(entry.Message)
+	if entry.Fields != nil {
+		fields := make([]Field, len(entry.Fields))
+		for i, f := range entry.Fields {
+			fields[i] = Field{Key: f.Key, Value: s.maskSecretsIn(f.Value)}
+		}
+		entry.Fields = fields
+	}
+	return entry
+}
+
+// maskSecretsIn masks the secrets in the strings of a value, walking maps, slices and structs
+func (s *settings) maskSecretsIn(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return s.maskSecretsInText(v)
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			masked[key] = s.maskSecretsIn(val)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, val := range v {
+			masked[i] = s.maskSecretsIn(val)
+		}
+		return masked
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr:
+		if tree, ok := toJSONTree(value); ok {
+			return s.maskSecretsIn(tree)
+		}
+	}
+	return value
+}
+
+// maskSecretsInText applies the built-in and the custom detectors to the text
+func (s *settings) maskSecretsInText(text string) string {
+	for _, d := range builtinDetectors {
+		text = d.mask(text, s.secretCounters)
+	}
+	for _, d := range s.detectors {
+		text = d.mask(text, s.secretCounters)
+	}
+	return text
+}
+
+// mask replaces the valid matches of the detector with the obscured value, counting them
+func (d Detector) mask(text string, counters *secretCounters) string {
+	matches := d.Pattern.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+
+	var b strings.Builder
+	last, n := 0, 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		if d.Validate != nil && !d.Validate(text[start:end]) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(obscuredValue)
+		last = end
+		n++
+	}
+	if n == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+
+	if counters != nil {
+		counters.add(d.Name, n)
+	}
+	return b.String()
+}
+
+// luhnValid tells if the digits of a card number satisfy the Luhn checksum
+func luhnValid(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
+
+// ibanValid tells if an IBAN satisfies the ISO 13616 mod-97 checksum
+func ibanValid(iban string) bool {
+	iban = strings.ReplaceAll(iban, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	remainder := 0
+	for _, c := range iban[4:] + iban[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
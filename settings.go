@@ -0,0 +1,281 @@
+package noodlog
+
+import "time"
+
+// apply overrides the settings with the non-nil values of configs
+func (s *settings) apply(configs Configs) {
+	if configs.LogLevel != nil {
+		s.setLevel(getLogLevel(*configs.LogLevel))
+	}
+	if configs.LevelOverrides != nil {
+		s.setLevelOverrides(configs.LevelOverrides)
+	}
+	if configs.LogWriter != nil {
+		s.logWriter = configs.LogWriter
+	}
+	if configs.Format != nil {
+		s.setFormat(*configs.Format)
+	}
+	if configs.Encoder != nil {
+		s.encoder = configs.Encoder
+	}
+	if configs.Preset != nil {
+		s.setPreset(*configs.Preset)
+	}
+	if configs.TimeFormat != nil {
+		s.timeFormat = *configs.TimeFormat
+	}
+	if configs.Schema != nil {
+		s.setSchema(configs.Schema)
+	}
+	if configs.UTC != nil {
+		s.utc = *configs.UTC
+	}
+	if configs.Clock != nil {
+		s.clock = configs.Clock
+	}
+	if configs.JSONPrettyPrint != nil {
+		s.prettyPrint = *configs.JSONPrettyPrint
+	}
+	if configs.TraceCaller != nil {
+		s.traceCaller = *configs.TraceCaller
+	}
+	if configs.SinglePointTracing != nil {
+		s.setSinglePointTracing(*configs.SinglePointTracing)
+	}
+	if configs.Colors != nil {
+		s.colors = *configs.Colors
+	}
+	if configs.CustomColors != nil {
+		s.setCustomColors(*configs.CustomColors)
+	}
+	if configs.ObscureSensitiveData != nil {
+		s.obscureSensitiveData = *configs.ObscureSensitiveData
+		if s.obscureSensitiveData {
+			s.setSensitiveParams(nil)
+		}
+	}
+	if configs.SensitiveParams != nil {
+		s.setSensitiveParams(configs.SensitiveParams)
+	}
+	if configs.Redactions != nil {
+		s.setRedactions(configs.Redactions)
+	}
+	if configs.DefaultRedaction != nil {
+		s.defaultRedaction = configs.DefaultRedaction
+	}
+	if configs.Detectors != nil {
+		s.setDetectors(configs.Detectors)
+	}
+	if configs.DetectSecrets != nil {
+		s.setSecretDetection(*configs.DetectSecrets)
+	}
+	if configs.Async != nil {
+		var options *AsyncOptions
+		if *configs.Async {
+			options = &AsyncOptions{}
+			if configs.AsyncOptions != nil {
+				options = configs.AsyncOptions
+			}
+		}
+		s.updateAsync(options)
+	} else if configs.AsyncOptions != nil && s.async != nil {
+		s.updateAsync(configs.AsyncOptions)
+	}
+	if configs.Sinks != nil {
+		sinks := make([]Sink, 0, len(configs.Sinks))
+		for _, sink := range configs.Sinks {
+			if sink != nil {
+				sinks = append(sinks, *sink)
+			}
+		}
+		s.setSinks(sinks)
+	}
+	if configs.ContextExtractors != nil {
+		s.contextExtractors = append([]ContextExtractor(nil), configs.ContextExtractors...)
+	}
+}
+
+// setSinglePointTracing enables the tracing of the caller of a wrapper function, or of the direct caller when disabled
+func (s *settings) setSinglePointTracing(enabled bool) {
+	if enabled {
+		s.traceCaller = true
+		s.traceCallerLevel = 6
+	} else {
+		s.traceCallerLevel = 5
+	}
+}
+
+// setCustomColors overrides the colors of the levels for which a valid custom color is specified
+func (s *settings) setCustomColors(colors CustomColors) {
+	custom := map[string]interface{}{
+		traceLabel: colors.Trace,
+		debugLabel: colors.Debug,
+		infoLabel:  colors.Info,
+		warnLabel:  colors.Warn,
+		errorLabel: colors.Error,
+	}
+
+	for name, c := range colors.Levels {
+		if level, err := ParseLevel(name); err == nil {
+			custom[level.String()] = c
+		}
+	}
+
+	empty := Color{}
+	for label, c := range custom {
+		if color := detectColor(c); color != empty {
+			s.colorMap[label] = color.toCode()
+		}
+	}
+}
+
+// setSensitiveParams stores a copy of params, so that the caller can't modify them while logging
+func (s *settings) setSensitiveParams(params []string) {
+	if params == nil {
+		s.sensitiveParams = nil
+		return
+	}
+	s.sensitiveParams = append(make([]string, 0, len(params)), params...)
+}
+
+// setFormat selects one of the built-in encoders, falling back to JSON for unknown formats.
+// The JSON encoder is left unset so that it follows the pretty printing setting.
+func (s *settings) setFormat(format string) {
+	s.encoder = nil
+	if _, isJSON := getEncoder(format).(JSONEncoder); !isJSON {
+		s.encoder = getEncoder(format)
+	}
+}
+
+// now returns the current time according to the logger clock
+func (s *settings) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
+// newEntry returns an entry with the given level and time, rendered as configured on the logger
+func (s *settings) newEntry(level string, t time.Time) Entry {
+	if s.utc {
+		t = t.UTC()
+	}
+	return Entry{Level: level, Time: t, timeFormat: s.timeFormat, schema: s.schema}
+}
+
+// getEncoder returns the encoder of the logger: JSON honoring the pretty printing setting when not specified
+func (s *settings) getEncoder() Encoder {
+	if s.encoder != nil {
+		return s.encoder
+	}
+	return JSONEncoder{PrettyPrint: s.prettyPrint}
+}
+
+// Configs returns a snapshot of the effective configs of the logger, which can be serialized to JSON
+// or passed to SetConfigs to configure another logger the same way. The fields and the name of the logger
+// are not part of its configs.
+func (l *Logger) Configs() Configs {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.snapshot()
+}
+
+// snapshot returns the settings as Configs, with copies of their slices and maps
+func (s *settings) snapshot() Configs {
+	configs := Configs{
+		LogLevel:             pointerOfString(Level(s.level()).String()),
+		LogWriter:            s.logWriter,
+		TimeFormat:           pointerOfString(s.timeFormat),
+		UTC:                  pointerOfBool(s.utc),
+		Clock:                s.clock,
+		JSONPrettyPrint:      pointerOfBool(s.prettyPrint),
+		TraceCaller:          pointerOfBool(s.traceCaller),
+		SinglePointTracing:   pointerOfBool(s.traceCaller && s.traceCallerLevel == 6),
+		Colors:               pointerOfBool(s.colors),
+		CustomColors:         s.customColors(),
+		ObscureSensitiveData: pointerOfBool(s.obscureSensitiveData),
+		SensitiveParams:      append([]string(nil), s.sensitiveParams...),
+		DefaultRedaction:     s.defaultRedaction,
+		DetectSecrets:        pointerOfBool(s.detectSecrets),
+		Detectors:            append([]Detector(nil), s.detectors...),
+		Async:                pointerOfBool(s.async != nil),
+		ContextExtractors:    append([]ContextExtractor(nil), s.contextExtractors...),
+	}
+
+	switch encoder := s.encoder.(type) {
+	case nil:
+		configs.Format = pointerOfString(jsonFormat)
+	case LogfmtEncoder:
+		configs.Format = pointerOfString(logfmtFormat)
+	case ConsoleEncoder:
+		configs.Format = pointerOfString(consoleFormat)
+	case presetEncoder:
+		configs.Preset = pointerOfString(encoder.name)
+	default:
+		configs.Encoder = encoder
+	}
+	if overrides := s.levelOverrides(); len(overrides) > 0 {
+		configs.LevelOverrides = make(map[string]string, len(overrides))
+		for _, o := range overrides {
+			configs.LevelOverrides[o.pattern] = Level(o.level).String()
+		}
+	}
+	if s.schema != nil {
+		schema := *s.schema
+		schema.Order = append([]string(nil), s.schema.Order...)
+		configs.Schema = &schema
+	}
+	if len(s.redactions) > 0 {
+		configs.Redactions = make(map[string]Redaction, len(s.redactions))
+		for param, redaction := range s.redactions {
+			configs.Redactions[param] = redaction
+		}
+	}
+	if s.async != nil {
+		options := s.async.options
+		configs.AsyncOptions = &options
+	}
+	for i := range s.sinks {
+		sink := s.sinks[i]
+		sink.sensitiveParams = copyStrings(sink.sensitiveParams)
+		configs.Sinks = append(configs.Sinks, &sink)
+	}
+	return configs
+}
+
+// customColors returns the colors of the levels: the built-in ones, and the others which have a color
+// set on the logger or with their registration
+func (s *settings) customColors() *CustomColors {
+	color := func(label string) interface{} {
+		code := levelColor(s.colorMap, label)
+		return Color{Code: &code}
+	}
+	colors := &CustomColors{
+		Trace: color(traceLabel),
+		Debug: color(debugLabel),
+		Info:  color(infoLabel),
+		Warn:  color(warnLabel),
+		Error: color(errorLabel),
+	}
+
+	labels := map[string]bool{}
+	for label := range s.colorMap {
+		labels[label] = true
+	}
+	levelsMu.RLock()
+	for label := range levelColors {
+		labels[label] = true
+	}
+	levelsMu.RUnlock()
+	for _, builtin := range []string{traceLabel, debugLabel, infoLabel, warnLabel, errorLabel} {
+		delete(labels, builtin)
+	}
+	for label := range labels {
+		if colors.Levels == nil {
+			colors.Levels = map[string]interface{}{}
+		}
+		colors.Levels[label] = color(label)
+	}
+	return colors
+}
@@ -0,0 +1,177 @@
+package noodlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoggerConfigs(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).SetConfigs(Configs{
+		LogLevel:             LevelDebug,
+		LevelOverrides:       map[string]string{"db": "trace"},
+		Format:               FormatLogfmt,
+		TraceCaller:          Enable,
+		SinglePointTracing:   Enable,
+		Colors:               Enable,
+		CustomColors:         &CustomColors{Warn: NewColorRGB(255, 136, 0)},
+		ObscureSensitiveData: Enable,
+		SensitiveParams:      []string{"password"},
+	})
+
+	configs := l.Configs()
+	if *configs.LogLevel != debugLabel || *configs.Format != logfmtFormat || configs.LevelOverrides["db"] != traceLabel {
+		t.Errorf(errorFmt, "TestLoggerConfigs", "debug, logfmt, db=trace", configs)
+	}
+	if !*configs.TraceCaller || !*configs.SinglePointTracing || !*configs.Colors || !*configs.ObscureSensitiveData {
+		t.Errorf(errorFmt, "TestLoggerConfigs", "caller tracing, colors and obscuring enabled", configs)
+	}
+	if configs.LogWriter != &b || strings.Join(configs.SensitiveParams, ",") != "password" {
+		t.Errorf(errorFmt, "TestLoggerConfigs", "the buffer and [password]", configs)
+	}
+
+	configs.SensitiveParams[0] = "token"
+	if l.sensitiveParams[0] != "password" {
+		t.Errorf(errorFmt, "TestLoggerConfigs", "a copy of the sensitive params", l.sensitiveParams)
+	}
+
+	var c bytes.Buffer
+	clone := NewLogger().SetConfigs(l.Configs()).LogWriter(&c)
+	clone.Warn(`{"password":"Sup3rS3cr3t"}`)
+	l.Warn(`{"password":"Sup3rS3cr3t"}`)
+	expected := strings.SplitN(b.String(), "time=", 2)[0]
+	if actual := strings.SplitN(c.String(), "time=", 2)[0]; actual != expected || !strings.Contains(actual, "38;2;255;136;0") {
+		t.Errorf(errorFmt, "TestLoggerConfigs clone", expected, actual)
+	}
+}
+
+func TestConfigsTracingDisabled(t *testing.T) {
+	l := NewLogger().EnableSinglePointTracing().DisableTraceCaller()
+	configs := l.Configs()
+	if *configs.TraceCaller || *configs.SinglePointTracing {
+		t.Errorf(errorFmt, "TestConfigsTracingDisabled", "caller tracing disabled", configs)
+	}
+	if clone := NewLogger().SetConfigs(configs); clone.traceCaller != l.traceCaller {
+		t.Errorf(errorFmt, "TestConfigsTracingDisabled clone", l.traceCaller, clone.traceCaller)
+	}
+}
+
+func TestConfigsLevelColors(t *testing.T) {
+	if err := RegisterLevel("notice", 35, NewColor(Cyan)); err != nil {
+		t.Fatalf(errorFmt, "TestConfigsLevelColors", nil, err)
+	}
+	l := NewLogger().SetConfigs(Configs{CustomColors: &CustomColors{Levels: map[string]interface{}{"PANIC": Red}}})
+
+	configs := l.Configs()
+	colors := configs.CustomColors.Levels
+	if detectColor(colors[panicLabel]).toCode() != NewColor(Red).toCode() || detectColor(colors["notice"]).toCode() != NewColor(Cyan).toCode() {
+		t.Errorf(errorFmt, "TestConfigsLevelColors", "red panic and cyan notice", colors)
+	}
+	if _, ok := colors[fatalLabel]; ok {
+		t.Errorf(errorFmt, "TestConfigsLevelColors", "no color for fatal", colors)
+	}
+
+	data, _ := json.Marshal(configs)
+	if !strings.Contains(string(data), `"panic":"red"`) || !strings.Contains(string(data), `"notice":"cyan"`) {
+		t.Errorf(errorFmt, "TestConfigsLevelColors JSON", "panic and notice colors", string(data))
+	}
+	var unmarshaled Configs
+	if err := json.Unmarshal(data, &unmarshaled); err != nil || unmarshaled.Validate() != nil {
+		t.Fatalf(errorFmt, "TestConfigsLevelColors JSON", nil, err)
+	}
+	if clone := NewLogger().SetConfigs(unmarshaled); clone.colorMap[panicLabel] != NewColor(Red).toCode() {
+		t.Errorf(errorFmt, "TestConfigsLevelColors clone", NewColor(Red).toCode(), clone.colorMap[panicLabel])
+	}
+
+	invalid := Configs{CustomColors: &CustomColors{Levels: map[string]interface{}{"verbose": Red, "panic": 42}}}
+	err := invalid.Validate()
+	if err == nil || !strings.Contains(err.Error(), `CustomColors.Levels: unknown level "verbose"`) || !strings.Contains(err.Error(), `CustomColors.Levels["panic"]: unsupported type`) {
+		t.Errorf(errorFmt, "TestConfigsLevelColors invalid", "the unknown level and the unsupported color", err)
+	}
+}
+
+func TestConfigsUnregisteredLevel(t *testing.T) {
+	configs := NewLogger().SetLevel(Level(35)).Configs()
+	if err := configs.Validate(); err != nil {
+		t.Fatalf(errorFmt, "TestConfigsUnregisteredLevel", nil, err)
+	}
+	if actual := NewLogger().SetConfigs(configs).GetLevel(); actual != Level(35) {
+		t.Errorf(errorFmt, "TestConfigsUnregisteredLevel", Level(35), actual)
+	}
+
+	data, _ := json.Marshal(configs)
+	var unmarshaled Configs
+	if err := json.Unmarshal(data, &unmarshaled); err != nil || unmarshaled.Validate() != nil {
+		t.Errorf(errorFmt, "TestConfigsUnregisteredLevel JSON", nil, err)
+	}
+}
+
+func TestConfigsMarshalJSONSinks(t *testing.T) {
+	l := NewLogger().SetSinks(
+		NewSink(os.Stderr).Level(errorLabel).Format(logfmtFormat).EnableObscureSensitiveData([]string{"token"}),
+		NewSink(&bytes.Buffer{}).EnableColors(),
+	)
+	data, err := json.Marshal(l.Configs())
+	if err != nil {
+		t.Fatalf(errorFmt, "TestConfigsMarshalJSONSinks", nil, err)
+	}
+	expected := `"sinks":[{"output":"stderr","level":"error","format":"logfmt","obscureSensitiveData":true,"sensitiveParams":["token"]},{"writer":"*bytes.Buffer","colors":true}]`
+	if !strings.Contains(string(data), expected) || strings.Contains(string(data), `"output":"stdout"`) {
+		t.Errorf(errorFmt, "TestConfigsMarshalJSONSinks", expected, string(data))
+	}
+
+	var decoded Configs
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Sinks) != 1 || decoded.LogWriter != nil {
+		t.Fatalf(errorFmt, "TestConfigsMarshalJSONSinks", "the stderr sink only", decoded.Sinks)
+	}
+	if sink := decoded.Sinks[0]; sink.Writer() != os.Stderr || *sink.Options().Level != errorLabel || sink.Options().SensitiveParams[0] != "token" {
+		t.Errorf(errorFmt, "TestConfigsMarshalJSONSinks", "the stderr sink", sink.Options())
+	}
+
+	for _, invalid := range []string{`{"sinks":[{"output":"stderr","level":"verbose"}]}`, `{"sinks":[{"level":"info"}]}`, `{"sinks":[{"output":"stderr","format":"xml"}]}`} {
+		if err := json.Unmarshal([]byte(invalid), &decoded); err == nil {
+			t.Errorf(errorFmt, "TestConfigsMarshalJSONSinks "+invalid, "an error", nil)
+		}
+	}
+}
+
+func TestConfigsMarshalJSON(t *testing.T) {
+	configs := NewLogger().LogWriter(os.Stderr).Preset("gcp").Configs()
+	data, err := json.Marshal(configs)
+	if err != nil {
+		t.Fatalf(errorFmt, "TestConfigsMarshalJSON", nil, err)
+	}
+	for _, expected := range []string{`"logLevel":"info"`, `"output":"stderr"`, `"preset":"gcp"`, `"traceCaller":true`, `"debug":"green"`, `"info":"default"`} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf(errorFmt, "TestConfigsMarshalJSON", expected, string(data))
+		}
+	}
+
+	var decoded Configs
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Validate() != nil {
+		t.Fatalf(errorFmt, "TestConfigsMarshalJSON", nil, err)
+	}
+	if redecoded, _ := json.Marshal(decoded); string(redecoded) != string(data) {
+		t.Errorf(errorFmt, "TestConfigsMarshalJSON round trip", string(data), string(redecoded))
+	}
+
+	colorsMap := map[string]interface{}{
+		`"#ff8800"`:              NewColorRGB(255, 136, 0),
+		`"red"`:                  NewColor(Red),
+		`"cyan"`:                 Cyan,
+		`"\u001b[31m\u001b[43m"`: NewColor(Red).Background(Yellow),
+	}
+	for expected, color := range colorsMap {
+		if actual, _ := json.Marshal(newFileColor(color)); string(actual) != expected {
+			t.Errorf(errorFmt, "TestConfigsMarshalJSON colors", expected, string(actual))
+		}
+	}
+
+	data, _ = json.Marshal(NewLogger().LogWriter(&bytes.Buffer{}).Configs())
+	if expected := `"writer":"*bytes.Buffer"`; !strings.Contains(string(data), expected) {
+		t.Errorf(errorFmt, "TestConfigsMarshalJSON writer", expected, string(data))
+	}
+}
@@ -0,0 +1,317 @@
+package noodlog
+
+import (
+	"fmt"
+	"io"
+)
+
+// Sink struct represents an output of a logger with its own level, format and obscuring settings.
+// The settings which are not specified are inherited from the logger.
+type Sink struct {
+	writer               io.Writer
+	level                *int
+	encoder              Encoder
+	json                 bool
+	prettyPrint          *bool
+	colors               *bool
+	obscureSensitiveData *bool
+	sensitiveParams      []string
+}
+
+// SinkOptions struct contains the settings of a Sink, the nil ones being inherited from the logger
+type SinkOptions struct {
+	// Level is the minimum level printed by the sink
+	Level *string
+	// Format is one of the built-in formats: "json", "logfmt" or "console"
+	Format *string
+	// Encoder is a custom encoder, which takes precedence over Format
+	Encoder Encoder
+	// JSONPrettyPrint enables the JSON pretty printing
+	JSONPrettyPrint *bool
+	// Colors enables the colored logs
+	Colors *bool
+	// ObscureSensitiveData enables the sensitive data obscuration
+	ObscureSensitiveData *bool
+	// SensitiveParams are the params obscured by the sink, nil inherits the logger ones
+	SensitiveParams []string
+}
+
+// NewSink func is the constructor of a Sink writing to w
+func NewSink(w io.Writer) *Sink {
+	return &Sink{writer: w}
+}
+
+// NewSinkWithOptions func is the constructor of a Sink writing to w with the given options
+func NewSinkWithOptions(w io.Writer, options SinkOptions) *Sink {
+	s := NewSink(w)
+	if options.Level != nil {
+		s.Level(*options.Level)
+	}
+	if options.Format != nil {
+		s.Format(*options.Format)
+	}
+	if options.Encoder != nil {
+		s.SetEncoder(options.Encoder)
+	}
+	if options.JSONPrettyPrint != nil {
+		s.prettyPrint = pointerOfBool(*options.JSONPrettyPrint)
+	}
+	if options.Colors != nil {
+		s.colors = pointerOfBool(*options.Colors)
+	}
+	if options.ObscureSensitiveData != nil {
+		s.obscureSensitiveData = pointerOfBool(*options.ObscureSensitiveData)
+		s.sensitiveParams = copyStrings(options.SensitiveParams)
+	}
+	return s
+}
+
+// Writer returns the writer of the sink
+func (s *Sink) Writer() io.Writer {
+	return s.writer
+}
+
+// Options returns the settings of the sink, so that an equivalent sink can be built with NewSinkWithOptions
+func (s *Sink) Options() SinkOptions {
+	options := SinkOptions{
+		JSONPrettyPrint:      copyBool(s.prettyPrint),
+		Colors:               copyBool(s.colors),
+		ObscureSensitiveData: copyBool(s.obscureSensitiveData),
+		SensitiveParams:      copyStrings(s.sensitiveParams),
+	}
+	if s.level != nil {
+		options.Level = pointerOfString(Level(*s.level).String())
+	}
+	switch s.encoder.(type) {
+	case nil:
+		if s.json {
+			options.Format = pointerOfString(jsonFormat)
+		}
+	case LogfmtEncoder:
+		options.Format = pointerOfString(logfmtFormat)
+	case ConsoleEncoder:
+		options.Format = pointerOfString(consoleFormat)
+	default:
+		options.Encoder = s.encoder
+	}
+	return options
+}
+
+// Level func let you establish the minimum log level printed by the sink
+func (s *Sink) Level(level string) *Sink {
+	logLevel := getLogLevel(level)
+	s.level = &logLevel
+	return s
+}
+
+// Format function let you choose one of the built-in formats for the sink: "json", "logfmt" or "console",
+// falling back to JSON for unknown formats whatever the format of the logger
+func (s *Sink) Format(format string) *Sink {
+	s.encoder = getEncoder(format)
+	s.json = false
+	if _, isJSON := s.encoder.(JSONEncoder); isJSON || s.encoder == nil {
+		// the JSON encoder is resolved with the sink output, so that it follows the pretty printing setting
+		s.encoder = nil
+		s.json = true
+	}
+	return s
+}
+
+// SetEncoder function let you define a custom Encoder for the sink
+func (s *Sink) SetEncoder(encoder Encoder) *Sink {
+	s.encoder = encoder
+	s.json = false
+	return s
+}
+
+// EnableJSONPrettyPrint func let you enable JSON pretty printing for the sink
+func (s *Sink) EnableJSONPrettyPrint() *Sink {
+	s.prettyPrint = pointerOfBool(true)
+	return s
+}
+
+// DisableJSONPrettyPrint func let you disable JSON pretty printing for the sink
+func (s *Sink) DisableJSONPrettyPrint() *Sink {
+	s.prettyPrint = pointerOfBool(false)
+	return s
+}
+
+// EnableColors function let you enable colored logs for the sink
+func (s *Sink) EnableColors() *Sink {
+	s.colors = pointerOfBool(true)
+	return s
+}
+
+// DisableColors function let you disable colored logs for the sink
+func (s *Sink) DisableColors() *Sink {
+	s.colors = pointerOfBool(false)
+	return s
+}
+
+// EnableObscureSensitiveData enables sensitive data obscuration for the sink, nil params inherits the logger ones
+func (s *Sink) EnableObscureSensitiveData(params []string) *Sink {
+	s.obscureSensitiveData = pointerOfBool(true)
+	s.sensitiveParams = copyStrings(params)
+	return s
+}
+
+// DisableObscureSensitiveData disables sensitive data obscuration for the sink
+func (s *Sink) DisableObscureSensitiveData() *Sink {
+	s.obscureSensitiveData = pointerOfBool(false)
+	return s
+}
+
+// AddSink function adds an output to the logger. Once a logger has some sinks, the records
+// are written only to them, each one following its settings, and the log writer is ignored.
+func (l *Logger) AddSink(sink *Sink) *Logger {
+	return l.update(func(s *settings) {
+		s.setSinks(append(s.sinks[:len(s.sinks):len(s.sinks)], *sink))
+	})
+}
+
+// SetSinks function replaces all the sinks of the logger; with no sinks the records are written to the log writer
+func (l *Logger) SetSinks(sinks ...*Sink) *Logger {
+	return l.update(func(s *settings) {
+		s.setSinks(nil)
+		for _, sink := range sinks {
+			s.setSinks(append(s.sinks, *sink))
+		}
+	})
+}
+
+// setSinks stores the sinks, ignoring the ones without a writer. The sensitive params are copied,
+// so that the sinks stored aren't affected by the later changes of the given ones.
+func (s *settings) setSinks(sinks []Sink) {
+	s.sinks = nil
+	for _, sink := range sinks {
+		if sink.writer != nil {
+			sink.sensitiveParams = copyStrings(sink.sensitiveParams)
+			s.sinks = append(s.sinks, sink)
+		}
+	}
+}
+
+// output represents the resolved settings of a destination of the records
+type output struct {
+	writer           io.Writer
+	level            int
+	encoder          Encoder
+	colors           bool
+	sensitiveParams  []string
+	redactions       map[string]Redaction
+	defaultRedaction Redaction
+}
+
+// outputs returns the destinations of the records: the sinks, or the log writer when there are no sinks.
+// level is the level of the logger for the record, applied to the outputs without their own level.
+func (s *settings) outputs(level int) []output {
+	if len(s.sinks) == 0 {
+		o := s.defaultOutput()
+		o.level = level
+		return []output{o}
+	}
+	outputs := make([]output, len(s.sinks))
+	for i, sink := range s.sinks {
+		outputs[i] = s.sinkOutput(sink, level)
+	}
+	return outputs
+}
+
+// defaultOutput returns the output described by the logger settings
+func (s *settings) defaultOutput() output {
+	o := output{
+		writer:  s.logWriter,
+		level:   s.level(),
+		encoder: s.getEncoder(),
+		colors:  s.colors,
+	}
+	if s.obscureSensitiveData {
+		o.sensitiveParams = s.sensitiveParams
+	}
+	o.redactions = s.redactions
+	o.defaultRedaction = s.defaultRedaction
+	return o
+}
+
+// sinkOutput returns the output described by the sink, with the missing settings taken from the logger
+func (s *settings) sinkOutput(sink Sink, level int) output {
+	o := s.defaultOutput()
+	o.writer = sink.writer
+	o.level = level
+	if sink.level != nil {
+		o.level = *sink.level
+	}
+	if sink.encoder != nil {
+		o.encoder = sink.encoder
+	} else if sink.json || sink.prettyPrint != nil {
+		prettyPrint := s.prettyPrint
+		if sink.prettyPrint != nil {
+			prettyPrint = *sink.prettyPrint
+		}
+		o.encoder = JSONEncoder{PrettyPrint: prettyPrint}
+	}
+	if sink.colors != nil {
+		o.colors = *sink.colors
+	}
+	if sink.obscureSensitiveData != nil {
+		o.sensitiveParams = nil
+		if *sink.obscureSensitiveData {
+			o.sensitiveParams = s.sensitiveParams
+			if sink.sensitiveParams != nil {
+				o.sensitiveParams = sink.sensitiveParams
+			}
+		}
+	}
+	return o
+}
+
+// minLevel returns the lowest level printed by any output, given the level of the logger
+func (s *settings) minLevel(level int) int {
+	if len(s.sinks) == 0 {
+		return level
+	}
+	min := fatalLevel
+	for _, sink := range s.sinks {
+		if sink.level == nil && level < min {
+			min = level
+		} else if sink.level != nil && *sink.level < min {
+			min = *sink.level
+		}
+	}
+	return min
+}
+
+// encode converts the entry into a record, obscuring the sensitive params and applying the colors
+func (o output) encode(entry Entry, colorMap map[string]string) string {
+	entry = o.obscure(entry)
+	jsn, err := o.encoder.Encode(entry)
+	if err != nil {
+		jsn = []byte(fmt.Sprintf("noodlog: unable to encode %s record: %v", entry.Level, err))
+	}
+
+	logRecord := string(jsn)
+	if o.colors {
+		logRecord = fmt.Sprintf("%s%s%s", levelColor(colorMap, entry.Level), logRecord, colorReset)
+	}
+
+	return logRecord
+}
+
+// obscure returns a copy of the entry with the values of the sensitive params obscured in message and fields
+func (o output) obscure(entry Entry) Entry {
+	if len(o.sensitiveParams) == 0 {
+		return entry
+	}
+	r := getRedactor(o.sensitiveParams).withRedactions(o.redactions, o.defaultRedaction)
+	entry.Message = r.redact(entry.Message)
+	if len(entry.Fields) != 0 {
+		fields := make([]Field, 0, len(entry.Fields))
+		for _, f := range entry.Fields {
+			if value, keep := r.redactField(f); keep {
+				fields = append(fields, Field{Key: f.Key, Value: value})
+			}
+		}
+		entry.Fields = fields
+	}
+	return entry
+}
@@ -0,0 +1,144 @@
+package noodlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSinks(t *testing.T) {
+	var stdout, file, stderr bytes.Buffer
+	l := NewLogger().
+		EnableObscureSensitiveData([]string{"password"}).
+		AddSink(NewSink(&stdout).Level(debugLabel).Format(consoleFormat).EnableColors()).
+		AddSink(NewSink(&file).Level(infoLabel).DisableObscureSensitiveData()).
+		AddSink(NewSink(&stderr).Level(errorLabel).EnableJSONPrettyPrint())
+
+	l.Trace("trace record")
+	l.Debug("debug record")
+	l.Info(`{"user": "gyoza", "password": "Sup3rS3cr3t"}`)
+	l.Error("error record")
+
+	if actual := stdout.String(); strings.Contains(actual, "trace record") ||
+		!Matches(actual, colorMap[debugLabel]+"*:*:* DEBUG debug record"+colorReset) ||
+		!strings.Contains(actual, `INFO  {"password":"**********","user":"gyoza"}`) {
+		t.Errorf(errorFmt, "TestSinks stdout", "colored console debug, info and error records", actual)
+	}
+	if actual := file.String(); strings.Contains(actual, "debug record") ||
+		!strings.Contains(actual, `"message":{"password":"Sup3rS3cr3t","user":"gyoza"}`) ||
+		!strings.Contains(actual, `{"level":"error","message":"error record"`) {
+		t.Errorf(errorFmt, "TestSinks file", "compact JSON info and error records", actual)
+	}
+	if actual := stderr.String(); strings.Contains(actual, "gyoza") ||
+		!strings.Contains(actual, "{\n   \"level\": \"error\",\n   \"message\": \"error record\"") {
+		t.Errorf(errorFmt, "TestSinks stderr", "pretty error record", actual)
+	}
+}
+
+func TestSinksConfigs(t *testing.T) {
+	var writer, sink bytes.Buffer
+	l := NewLogger().SetConfigs(Configs{
+		LogWriter: &writer,
+		Sinks:     []*Sink{NewSink(&sink).Level(warnLabel), nil, NewSink(nil)},
+	})
+
+	if len(l.sinks) != 1 {
+		t.Fatalf(errorFmt, "TestSinksConfigs", 1, len(l.sinks))
+	}
+	if l.isEnabled(infoLabel) || !l.isEnabled(warnLabel) {
+		t.Errorf(errorFmt, "TestSinksConfigs", "enabled from warn", l.minLevel(l.level()))
+	}
+
+	l.Warn("hello")
+	if writer.Len() != 0 || !strings.Contains(sink.String(), "hello") {
+		t.Errorf(errorFmt, "TestSinksConfigs", "record only on the sink", writer.String()+sink.String())
+	}
+
+	l.SetSinks()
+	l.Warn("back to the writer")
+	if !strings.Contains(writer.String(), "back to the writer") {
+		t.Errorf(errorFmt, "TestSinksConfigs", "back to the writer", writer.String())
+	}
+}
+
+func TestSinkObscureParams(t *testing.T) {
+	testMap := map[*Sink]string{
+		NewSink(nil): `"password":"**********","token":"abc"`,
+		NewSink(nil).EnableObscureSensitiveData([]string{"token"}): `"password":"Sup3rS3cr3t","token":"**********"`,
+		NewSink(nil).DisableObscureSensitiveData():                 `"password":"Sup3rS3cr3t","token":"abc"`,
+	}
+
+	l := NewLogger().EnableObscureSensitiveData([]string{"password"})
+	for sink, expected := range testMap {
+		o := l.sinkOutput(*sink, l.level())
+		entry := o.obscure(Entry{Level: infoLabel, Fields: []Field{{"password", "Sup3rS3cr3t"}, {"token", "abc"}}})
+		actual, _ := JSONEncoder{}.Encode(entry)
+		if !strings.Contains(string(actual), expected) {
+			t.Errorf(errorFmt, "TestSinkObscureParams", expected, string(actual))
+		}
+	}
+}
+
+func TestSinkJSONFormat(t *testing.T) {
+	var console, compact, pretty, unknown bytes.Buffer
+	l := NewLogger().Format("console").EnableJSONPrettyPrint().SetSinks(
+		NewSink(&console),
+		NewSink(&compact).Format("json").DisableJSONPrettyPrint(),
+		NewSink(&pretty).Format("JSON"),
+		NewSink(&unknown).Format("xml"),
+	)
+
+	l.Info("hello")
+	if actual := console.String(); !strings.Contains(actual, "INFO") || strings.Contains(actual, "{") {
+		t.Errorf(errorFmt, "TestSinkJSONFormat console", "a console line", actual)
+	}
+	if actual := compact.String(); !strings.HasPrefix(actual, `{"level":"info","message":"hello"`) {
+		t.Errorf(errorFmt, "TestSinkJSONFormat compact", "a compact JSON record", actual)
+	}
+	if actual := pretty.String(); !strings.HasPrefix(actual, "{\n") {
+		t.Errorf(errorFmt, "TestSinkJSONFormat pretty", "a pretty printed JSON record", actual)
+	}
+	if actual := unknown.String(); !strings.HasPrefix(actual, "{") {
+		t.Errorf(errorFmt, "TestSinkJSONFormat unknown", "a JSON record", actual)
+	}
+}
+
+func TestSinkParamsCopied(t *testing.T) {
+	var b bytes.Buffer
+	params := []string{"password"}
+	l := NewLogger().AddSink(NewSink(&b).EnableObscureSensitiveData(params))
+
+	params[0] = "token"
+	l.Info(map[string]string{"password": "Sup3rS3cr3t", "token": "abc"})
+	if expected := `"password":"**********","token":"abc"`; !strings.Contains(b.String(), expected) {
+		t.Errorf(errorFmt, "TestSinkParamsCopied", expected, b.String())
+	}
+}
+
+func TestNewSinkWithOptions(t *testing.T) {
+	var b bytes.Buffer
+	options := SinkOptions{
+		Level:                LevelWarn,
+		Format:               pointerOfString(logfmtFormat),
+		Colors:               Disable,
+		ObscureSensitiveData: Enable,
+		SensitiveParams:      []string{"token"},
+	}
+	sink := NewSinkWithOptions(&b, options)
+	options.SensitiveParams[0] = "password"
+
+	l := NewLogger().AddSink(sink)
+	l.Info("info")
+	l.Warnw("warn", "token", "abc")
+	if actual := b.String(); strings.Contains(actual, "info") || !strings.Contains(actual, "level=warn msg=warn") || !strings.Contains(actual, "token=**********") {
+		t.Errorf(errorFmt, "TestNewSinkWithOptions", "only the warn record in logfmt", actual)
+	}
+
+	actual := sink.Options()
+	if *actual.Level != warnLabel || *actual.Format != logfmtFormat || *actual.Colors || !*actual.ObscureSensitiveData || actual.SensitiveParams[0] != "token" || actual.JSONPrettyPrint != nil {
+		t.Errorf(errorFmt, "TestNewSinkWithOptions options", options, actual)
+	}
+	if sink.Writer() != &b {
+		t.Errorf(errorFmt, "TestNewSinkWithOptions writer", &b, sink.Writer())
+	}
+}
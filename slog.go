@@ -0,0 +1,192 @@
+//go:build go1.21
+// +build go1.21
+
+package noodlog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler which prints the slog records through a noodlog Logger
+type SlogHandler struct {
+	logger *Logger
+	goas   []groupOrAttrs
+}
+
+// groupOrAttrs contains either a group name or some attributes added to a SlogHandler
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewSlogHandler returns a slog.Handler which routes the records to the given logger,
+// so that they are filtered, obscured, traced, colored and encoded as the logger ones
+func NewSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{logger: l}
+}
+
+// Enabled tells if the logger prints the records with the given slog level
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	h.logger.mu.RLock()
+	defer h.logger.mu.RUnlock()
+	return h.logger.isEnabled(slogLevelToLabel(level))
+}
+
+// Handle prints the slog record through the logger, emitting its attributes and the values extracted from ctx as fields
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var f slogFields
+	var groups []string
+	for _, goa := range h.goas {
+		if goa.group != "" {
+			groups = append(groups, goa.group)
+			continue
+		}
+		for _, a := range goa.attrs {
+			f.addAttr(groups, a)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		f.addAttr(groups, a)
+		return true
+	})
+
+	h.logger.printRecord(ctx, slogLevelToLabel(r.Level), r.Time, r.PC, r.Message, f.fields)
+	return nil
+}
+
+// WithAttrs returns a handler which adds the given attributes to every record
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(groupOrAttrs{attrs: attrs})
+}
+
+// WithGroup returns a handler which nests the following attributes under the given group
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(groupOrAttrs{group: name})
+}
+
+func (h *SlogHandler) withGroupOrAttrs(goa groupOrAttrs) *SlogHandler {
+	goas := make([]groupOrAttrs, len(h.goas), len(h.goas)+1)
+	copy(goas, h.goas)
+	return &SlogHandler{logger: h.logger, goas: append(goas, goa)}
+}
+
+// SetSlogHandler function let you use a slog.Handler as the sink of the records, in place of the encoder and the log writer
+func (l *Logger) SetSlogHandler(handler slog.Handler) *Logger {
+	return l.update(func(s *settings) {
+		s.handler = nil
+		if handler != nil {
+			s.handler = slogSink{handler: handler}
+		}
+	})
+}
+
+// slogSink converts the entries of a logger into slog records for a slog.Handler
+type slogSink struct {
+	handler slog.Handler
+}
+
+func (s slogSink) handleEntry(entry Entry) {
+	ctx := context.Background()
+	level := labelToSlogLevel(entry.Level)
+	if !s.handler.Enabled(ctx, level) {
+		return
+	}
+
+	r := slog.NewRecord(entry.Time, level, toText(entry.Message), 0)
+	if entry.File != "" {
+		r.AddAttrs(slog.String("file", entry.File), slog.String("function", entry.Function))
+	}
+	for _, f := range entry.Fields {
+		r.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	_ = s.handler.Handle(ctx, r)
+}
+
+// slogFields collects the slog attributes as fields, nesting the groups as maps
+type slogFields struct {
+	fields []Field
+}
+
+func (f *slogFields) addAttr(groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, ga := range attrs {
+			f.addAttr(groups, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	f.set(groups, a.Key, a.Value.Any())
+}
+
+// set stores the value under the given key, creating the maps of the groups when missing
+func (f *slogFields) set(groups []string, key string, value interface{}) {
+	if len(groups) == 0 {
+		f.fields = setField(f.fields, Field{Key: key, Value: value})
+		return
+	}
+
+	var group map[string]interface{}
+	for _, field := range f.fields {
+		if field.Key == groups[0] {
+			group, _ = field.Value.(map[string]interface{})
+		}
+	}
+	if group == nil {
+		group = map[string]interface{}{}
+		f.fields = setField(f.fields, Field{Key: groups[0], Value: group})
+	}
+
+	for _, name := range groups[1:] {
+		nested, ok := group[name].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			group[name] = nested
+		}
+		group = nested
+	}
+	group[key] = value
+}
+
+// slogLevelToLabel converts a slog level into the label of the nearest noodlog level below it
+func slogLevelToLabel(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return traceLabel
+	case level < slog.LevelInfo:
+		return debugLabel
+	case level < slog.LevelWarn:
+		return infoLabel
+	case level < slog.LevelError:
+		return warnLabel
+	default:
+		return errorLabel
+	}
+}
+
+// labelToSlogLevel converts the label of a noodlog level into a slog level by severity: the built-in levels are
+// 10 apart while the slog ones are 4 apart, so that a custom level between two built-in ones is converted between
+// the matching slog levels, e.g. 45 into WARN+2
+func labelToSlogLevel(label string) slog.Level {
+	level := levelOf(label)
+	if level == 0 {
+		return slog.LevelInfo
+	}
+	return slog.Level((level - infoLevel) * 4 / 10)
+}
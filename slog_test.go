@@ -0,0 +1,125 @@
+//go:build go1.21
+// +build go1.21
+
+package noodlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).Level(debugLabel).EnableObscureSensitiveData([]string{"password"})
+	log := slog.New(NewSlogHandler(l)).With("service", "noodlog").WithGroup("req")
+
+	log.Debug("hello", "id", 7, slog.Group("user", "name", "gyoza", "password", "Sup3rS3cr3t"))
+
+	expected := `"service":"noodlog","req":{"id":7,"user":{"name":"gyoza","password":"**********"}}}`
+	if actual := b.String(); !strings.HasPrefix(actual, `{"level":"debug","message":"hello"`) || !strings.Contains(actual, expected) {
+		t.Errorf(errorFmt, "TestSlogHandler", expected, actual)
+	}
+}
+
+func TestSlogHandlerEmptyGroup(t *testing.T) {
+	var b bytes.Buffer
+	log := slog.New(NewSlogHandler(NewLogger().LogWriter(&b))).WithGroup("empty")
+
+	log.Info("hello")
+
+	if actual := b.String(); strings.Contains(actual, "empty") {
+		t.Errorf(errorFmt, "TestSlogHandlerEmptyGroup", "no empty group", actual)
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	h := NewSlogHandler(NewLogger().Level(warnLabel))
+	testMap := map[slog.Level]bool{
+		slog.LevelDebug - 4: false,
+		slog.LevelDebug:     false,
+		slog.LevelInfo:      false,
+		slog.LevelWarn:      true,
+		slog.LevelError:     true,
+		slog.LevelError + 4: true,
+	}
+
+	for level, expected := range testMap {
+		if actual := h.Enabled(context.Background(), level); actual != expected {
+			t.Errorf(errorFmt, "TestSlogHandlerEnabled "+level.String(), expected, actual)
+		}
+	}
+}
+
+func TestSlogHandlerTraceCaller(t *testing.T) {
+	var b bytes.Buffer
+	log := slog.New(NewSlogHandler(NewLogger().LogWriter(&b).EnableTraceCaller()))
+
+	log.Info("hello")
+
+	expected := `"function":"github.com/gyozatech/noodlog.TestSlogHandlerTraceCaller"`
+	if actual := b.String(); !strings.Contains(actual, expected) || !strings.Contains(actual, "slog_test.go") {
+		t.Errorf(errorFmt, "TestSlogHandlerTraceCaller", expected, actual)
+	}
+}
+
+func TestSetSlogHandler(t *testing.T) {
+	var b bytes.Buffer
+	sink := slog.NewJSONHandler(&b, &slog.HandlerOptions{Level: slog.LevelWarn})
+	l := NewLogger().SetSlogHandler(sink).With("requestId", "a1b2")
+
+	l.Info("filtered by the slog handler")
+	l.Warn("hello")
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &rec); err != nil {
+		t.Fatalf(errorFmt, "TestSetSlogHandler", "a single JSON record", b.String())
+	}
+	if rec["level"] != "WARN" || rec["msg"] != "hello" || rec["requestId"] != "a1b2" {
+		t.Errorf(errorFmt, "TestSetSlogHandler", `level WARN, msg hello, requestId a1b2`, rec)
+	}
+}
+
+func TestLabelToSlogLevel(t *testing.T) {
+	testMap := map[string]slog.Level{
+		traceLabel: slog.LevelDebug - 4,
+		debugLabel: slog.LevelDebug,
+		infoLabel:  slog.LevelInfo,
+		warnLabel:  slog.LevelWarn,
+		errorLabel: slog.LevelError,
+		panicLabel: slog.LevelError + 4,
+		fatalLabel: slog.LevelError + 8,
+		"unknown":  slog.LevelInfo,
+	}
+
+	for label, expected := range testMap {
+		if actual := labelToSlogLevel(label); actual != expected {
+			t.Errorf(errorFmt, "TestLabelToSlogLevel "+label, expected, actual)
+		}
+	}
+}
+
+func TestSetSlogHandlerCustomLevel(t *testing.T) {
+	if err := RegisterLevel("audit", 45); err != nil {
+		t.Fatal(err)
+	}
+	audit, _ := ParseLevel("audit")
+
+	var b bytes.Buffer
+	sink := slog.NewJSONHandler(&b, &slog.HandlerOptions{Level: slog.LevelWarn + 1})
+	l := NewLogger().SetSlogHandler(sink)
+
+	l.Warn("filtered by the slog handler")
+	l.Log(audit, "user logged in")
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &rec); err != nil {
+		t.Fatalf(errorFmt, "TestSetSlogHandlerCustomLevel", "a single JSON record", b.String())
+	}
+	if rec["level"] != "WARN+2" || rec["msg"] != "user logged in" {
+		t.Errorf(errorFmt, "TestSetSlogHandlerCustomLevel", "level WARN+2, msg user logged in", rec)
+	}
+}
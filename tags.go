@@ -0,0 +1,284 @@
+package noodlog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	// tagName is the struct tag read by noodlog
+	tagName = "noodlog"
+	// sensitiveTag marks a struct field whose value is always obscured
+	sensitiveTag = "sensitive"
+	// omitTag marks a struct field which is never logged
+	omitTag = "-"
+)
+
+// Redactor interface can be implemented by the types which want to decide how they're logged:
+// noodlog logs the value returned by Redact in place of the original one, whatever the logger configuration
+type Redactor interface {
+	Redact() interface{}
+}
+
+var redactorType = reflect.TypeOf((*Redactor)(nil)).Elem()
+
+// taggedTypes caches whether a type may contain a Redactor or a noodlog tag, so that the other types are skipped
+var taggedTypes sync.Map
+
+// redactTagged returns the value to be logged in place of v, honoring the Redactor interface and the noodlog
+// struct tags at any depth. The values which don't contain any of them are returned untouched.
+func redactTagged(v interface{}) interface{} {
+	if v == nil || !mayBeTagged(reflect.TypeOf(v)) {
+		return v
+	}
+	redacted, changed := redactValue(reflect.ValueOf(v), visits{})
+	if !changed {
+		return v
+	}
+	return redacted
+}
+
+// visit identifies a pointer, a map or a slice being redacted
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// visits contains the pointers, the maps and the slices on the path of the value being redacted,
+// so that a cyclic value is logged as it is instead of being redacted endlessly
+type visits map[visit]bool
+
+// enter records the visit of a pointer, a map or a slice, returning false when it is already on the path
+func (vs visits) enter(v reflect.Value) (visit, bool) {
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	if vs[key] {
+		return key, false
+	}
+	vs[key] = true
+	return key, true
+}
+
+// redactValue converts the value into a JSON-like tree when it contains a Redactor or a tagged field
+func redactValue(v reflect.Value, visited visits) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, false
+	}
+	if redacted, ok := callRedactor(v); ok {
+		// the Redact method is not called again on its result, which may be of the same type
+		if tree, changed := redactContent(reflect.ValueOf(redacted), visited); changed {
+			return tree, true
+		}
+		return redacted, true
+	}
+	return redactContent(v, visited)
+}
+
+// redactContent applies the Redactor interface and the noodlog tags to the elements and the fields of the value.
+// A value containing itself is returned untouched.
+func redactContent(v reflect.Value, visited visits) (interface{}, bool) {
+	if !v.IsValid() || !mayBeTagged(v.Type()) {
+		return nil, false
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, false
+		}
+		key, ok := visited.enter(v)
+		if !ok {
+			return nil, false
+		}
+		defer delete(visited, key)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		return redactContent(v.Elem(), visited)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		return redactValue(v.Elem(), visited)
+	case reflect.Struct:
+		return redactStruct(v, visited)
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, v.Len())
+		changed := false
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+			if redacted, ok := redactValue(v.Index(i), visited); ok {
+				items[i], changed = redacted, true
+			}
+		}
+		return items, changed
+	case reflect.Map:
+		m := make(map[string]interface{}, v.Len())
+		changed := false
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			m[key] = iter.Value().Interface()
+			if redacted, ok := redactValue(iter.Value(), visited); ok {
+				m[key], changed = redacted, true
+			}
+		}
+		return m, changed
+	}
+	return nil, false
+}
+
+// redactStruct converts a struct into a map keyed by the JSON names of its fields, applying the noodlog tags
+func redactStruct(v reflect.Value, visited visits) (interface{}, bool) {
+	m := map[string]interface{}{}
+	changed := false
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		value := v.Field(i)
+		if omitEmpty && value.IsZero() {
+			continue
+		}
+
+		switch tag := field.Tag.Get(tagName); tag {
+		case omitTag:
+			changed = true
+			continue
+		case sensitiveTag:
+			changed = true
+			m[name] = obscuredValue
+			continue
+		}
+
+		if field.Anonymous && field.Tag.Get("json") == "" && indirectType(field.Type).Kind() == reflect.Struct {
+			if value.Kind() == reflect.Ptr && value.IsNil() {
+				continue
+			}
+			embedded, ok := redactStruct(reflect.Indirect(value), visited)
+			changed = changed || ok
+			for k, val := range embedded.(map[string]interface{}) {
+				if _, exists := m[k]; !exists {
+					m[k] = val
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		m[name] = value.Interface()
+		if redacted, ok := redactValue(value, visited); ok {
+			m[name], changed = redacted, true
+		}
+	}
+	return m, changed
+}
+
+// jsonFieldName returns the name of a struct field in its JSON representation
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+// callRedactor calls the Redact method of the value, also when it is implemented by its pointer
+func callRedactor(v reflect.Value) (interface{}, bool) {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, false
+	}
+	if v.Type().Implements(redactorType) {
+		if v.Kind() == reflect.Interface && v.IsNil() {
+			return nil, false
+		}
+		return v.Interface().(Redactor).Redact(), true
+	}
+	if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface && reflect.PtrTo(v.Type()).Implements(redactorType) {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		return ptr.Interface().(Redactor).Redact(), true
+	}
+	return nil, false
+}
+
+// mayBeTagged tells if the values of a type may contain a Redactor or a noodlog tag
+func mayBeTagged(t reflect.Type) bool {
+	if cached, ok := taggedTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	tagged, _ := inspectType(t, map[reflect.Type]bool{})
+	taggedTypes.Store(t, tagged)
+	return tagged
+}
+
+// inspectType tells if the values of a type may contain a Redactor or a noodlog tag. The types being inspected
+// are skipped, as a cycle doesn't add any tag, and the answers depending on them are not final and not cached.
+func inspectType(t reflect.Type, inspecting map[reflect.Type]bool) (tagged bool, final bool) {
+	if cached, ok := taggedTypes.Load(t); ok {
+		return cached.(bool), true
+	}
+	if inspecting[t] {
+		return false, false
+	}
+	if t.Implements(redactorType) || reflect.PtrTo(t).Implements(redactorType) {
+		return true, true
+	}
+
+	inspecting[t] = true
+	defer delete(inspecting, t)
+	final = true
+	inspect := func(t reflect.Type) bool {
+		tagged, ok := inspectType(t, inspecting)
+		final = final && ok
+		return tagged
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		tagged = true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		tagged = inspect(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField() && !tagged; i++ {
+			field := t.Field(i)
+			_, tagged = field.Tag.Lookup(tagName)
+			tagged = tagged || inspect(field.Type)
+		}
+	}
+	if tagged || final {
+		taggedTypes.Store(t, tagged)
+		return tagged, true
+	}
+	return false, false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,153 @@
+package noodlog
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type taggedCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password" noodlog:"sensitive"`
+	Session  string `noodlog:"-"`
+	Note     string `json:"note,omitempty"`
+}
+
+type taggedUser struct {
+	taggedCredentials
+	Token  testToken            `json:"token"`
+	Others []*taggedCredentials `json:"others"`
+	hidden string
+}
+
+type testToken string
+
+func (t testToken) Redact() interface{} {
+	return "tok-" + strings.Repeat("*", len(t))
+}
+
+type selfRedactor struct {
+	Secret string `json:"secret"`
+}
+
+func (s *selfRedactor) Redact() interface{} {
+	return selfRedactor{Secret: "hidden"}
+}
+
+type plainUser struct {
+	Name string `json:"name"`
+}
+
+func TestRedactTagged(t *testing.T) {
+	user := taggedUser{
+		taggedCredentials: taggedCredentials{Username: "gyoza", Password: "p4ss", Session: "s1"},
+		Token:             "abc",
+		Others:            []*taggedCredentials{{Username: "udon", Password: "p", Note: "n"}},
+		hidden:            "h",
+	}
+	expected := map[string]interface{}{
+		"username": "gyoza",
+		"password": obscuredValue,
+		"token":    "tok-***",
+		"others":   []interface{}{map[string]interface{}{"username": "udon", "password": obscuredValue, "note": "n"}},
+	}
+
+	if actual := redactTagged(user); !reflect.DeepEqual(actual, expected) {
+		t.Errorf(errorFmt, "TestRedactTagged", expected, actual)
+	}
+	if actual := redactTagged(&user); !reflect.DeepEqual(actual, expected) {
+		t.Errorf(errorFmt, "TestRedactTagged pointer", expected, actual)
+	}
+}
+
+func TestRedactTaggedUntouched(t *testing.T) {
+	plain := plainUser{Name: "gyoza"}
+	values := []interface{}{nil, 42, "text", plain, []plainUser{plain}, map[string]interface{}{"user": plain}}
+
+	for _, value := range values {
+		if actual := redactTagged(value); !reflect.DeepEqual(actual, value) {
+			t.Errorf(errorFmt, "TestRedactTaggedUntouched", value, actual)
+		}
+	}
+}
+
+func TestRedactTaggedSelfRedactor(t *testing.T) {
+	expected := selfRedactor{Secret: "hidden"}
+	if actual := redactTagged(selfRedactor{Secret: "s"}); actual != expected {
+		t.Errorf(errorFmt, "TestRedactTaggedSelfRedactor", expected, actual)
+	}
+}
+
+func TestLoggerHonorsTags(t *testing.T) {
+	var b bytes.Buffer
+	l := NewLogger().LogWriter(&b).With("user", taggedCredentials{Username: "gyoza", Password: "p4ss"})
+
+	l.Info(taggedCredentials{Username: "gyoza", Password: "p4ss", Session: "s1"})
+	expected := `{"level":"info","message":{"password":"**********","username":"gyoza"},"time":"*","user":{"password":"**********","username":"gyoza"}}`
+	if actual := b.String(); !Matches(actual, expected) {
+		t.Errorf(errorFmt, "TestLoggerHonorsTags", expected, actual)
+	}
+	b.Reset()
+
+	l.Info("token %v", testToken("secret"))
+	if actual := b.String(); !strings.Contains(actual, `"message":"token tok-******"`) {
+		t.Errorf(errorFmt, "TestLoggerHonorsTags", "token tok-******", actual)
+	}
+}
+
+type cyclicNode struct {
+	Name string      `json:"name"`
+	Next *cyclicNode `json:"next"`
+}
+
+type taggedCyclicNode struct {
+	Name   string            `json:"name"`
+	Secret string            `json:"secret" noodlog:"sensitive"`
+	Next   *taggedCyclicNode `json:"next"`
+}
+
+func TestRedactTaggedCyclic(t *testing.T) {
+	if mayBeTagged(reflect.TypeOf(cyclicNode{})) {
+		t.Errorf(errorFmt, "TestRedactTaggedCyclic", "an untagged recursive type", "tagged")
+	}
+	if !mayBeTagged(reflect.TypeOf(&taggedCyclicNode{})) {
+		t.Errorf(errorFmt, "TestRedactTaggedCyclic", "a tagged recursive type", "untagged")
+	}
+
+	n := &cyclicNode{Name: "a"}
+	n.Next = n
+	if actual := redactTagged(n); actual != n {
+		t.Errorf(errorFmt, "TestRedactTaggedCyclic", n, actual)
+	}
+
+	tagged := &taggedCyclicNode{Name: "a", Secret: "s"}
+	tagged.Next = tagged
+	expected := map[string]interface{}{"name": "a", "secret": obscuredValue, "next": tagged}
+	if actual := redactTagged(tagged); !reflect.DeepEqual(actual, expected) {
+		t.Errorf(errorFmt, "TestRedactTaggedCyclic tagged", expected, actual)
+	}
+
+	m := map[string]interface{}{"node": tagged}
+	m["self"] = m
+	if actual, ok := redactTagged(m).(map[string]interface{}); !ok || actual["node"].(map[string]interface{})["secret"] != obscuredValue {
+		t.Errorf(errorFmt, "TestRedactTaggedCyclic map", "the node redacted", actual)
+	}
+}
+
+func TestLoggerCyclicValue(t *testing.T) {
+	var b bytes.Buffer
+	n := &cyclicNode{Name: "a"}
+	n.Next = n
+	tagged := &taggedCyclicNode{Name: "a", Secret: "s"}
+	tagged.Next = tagged
+	l := NewLogger().LogWriter(&b)
+
+	l.Info(n)
+	l.Info(tagged)
+	l.With("node", tagged).Info("with")
+	l.Info("nodes", n, tagged)
+	if actual := strings.Count(b.String(), "\n"); actual != 4 {
+		t.Errorf(errorFmt, "TestLoggerCyclicValue", 4, actual)
+	}
+}
@@ -3,7 +3,6 @@ package noodlog
 import (
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"runtime"
 	"strings"
 )
@@ -16,6 +15,22 @@ func pointerOfBool(v bool) *bool {
 	return &v
 }
 
+// copyStrings returns a copy of the slice, nil if nil
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
+
+// copyBool returns a copy of the pointed bool, nil if nil
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	return pointerOfBool(*b)
+}
+
 func stringify(message []interface{}) string {
 	var b strings.Builder
 	for _, m := range message {
@@ -27,25 +42,9 @@ func stringify(message []interface{}) string {
 	return msg[:len(msg)-1]
 }
 
-func obscureParams(jsn string, sensitiveParams []string) string {
-	for _, param := range sensitiveParams {
-		jsn = obscureParam(jsn, param)
-	}
-	return jsn
-}
-
-func obscureParam(jsn string, param string) string {
-	rWithSlash := *regexp.MustCompile(`\\"` + param + `\\":.*?"(.*?)\\"`)
-	jsn = rWithSlash.ReplaceAllString(jsn, `\"`+param+`\": \"**********\"`)
-
-	rWithoutSlash := *regexp.MustCompile(`"` + param + `":.*?"(.*?)"`)
-	return rWithoutSlash.ReplaceAllString(jsn, `"`+param+`": "**********"`)
-}
-
 func strToObj(strMsg string) interface{} {
 	if byteMsg := []byte(strMsg); json.Valid(byteMsg) {
-		var obj interface{}
-		_ = json.Unmarshal(byteMsg, &obj)
+		obj, _ := decodeJSON(byteMsg)
 		return obj
 	}
 	return strMsg
@@ -61,3 +60,11 @@ func traceCaller(traceCallerLevel int) (file, function string) {
 	return fmt.Sprintf("%s:%d", frame.File, frame.Line),
 		frame.Function
 }
+
+// callerOf function retrieves the filename and the function of a program counter
+func callerOf(pc uintptr) (file, function string) {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	return fmt.Sprintf("%s:%d", frame.File, frame.Line),
+		frame.Function
+}
@@ -36,30 +36,6 @@ func TestStringify(t *testing.T) {
 	}
 }
 
-func TestObscureParam(t *testing.T) {
-	testData := map[string]string{
-		jsnSlashes: "{\"param1\": 1, \"param2\": \"**********\", \"param3\": {\"param4\": \"secret\"}}",
-		jsnRaw:     `{"param1": 1, "param2": "**********", "param3": {"param4": "secret"}}`,
-	}
-	for input, expected := range testData {
-		if actual := obscureParam(input, "param2"); actual != expected {
-			t.Errorf("TestObscureParam failed: expected %s, got %s", expected, actual)
-		}
-	}
-}
-
-func TestObscureParams(t *testing.T) {
-	testData := map[string]string{
-		jsnSlashes: "{\"param1\": 1, \"param2\": \"**********\", \"param3\": {\"param4\": \"**********\"}}",
-		jsnRaw:     `{"param1": 1, "param2": "**********", "param3": {"param4": "**********"}}`,
-	}
-	for input, expected := range testData {
-		if actual := obscureParams(input, []string{"param2", "param4"}); actual != expected {
-			t.Errorf("TestObscureParams failed: expected %s, got %s", expected, actual)
-		}
-	}
-}
-
 func TestStrToObj(t *testing.T) {
 	simpleMessageStr := "simple-message"
 	var simpleMessage interface{} = simpleMessageStr
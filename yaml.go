@@ -0,0 +1,304 @@
+package noodlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a significant line of a YAML document, without its indentation and comment
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// yamlParser converts the subset of YAML used by the configs files into values marshalable as JSON:
+// block mappings and sequences, flow sequences of scalars, plain and quoted scalars and comments.
+// Anchors, tags, block scalars and multiple documents are not supported.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// yamlToJSON converts a YAML document into JSON
+func yamlToJSON(data []byte) ([]byte, error) {
+	p := &yamlParser{}
+	if err := p.scan(string(data)); err != nil {
+		return nil, err
+	}
+	if len(p.lines) == 0 {
+		return []byte("{}"), nil
+	}
+	value, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf(p.lines[p.pos], "unexpected indentation")
+	}
+	return json.Marshal(value)
+}
+
+// scan splits the document into its significant lines
+func (p *yamlParser) scan(doc string) error {
+	for i, raw := range strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n") {
+		line := yamlLine{number: i + 1}
+		trimmed := strings.TrimLeft(raw, " ")
+		line.indent = len(raw) - len(trimmed)
+		if strings.HasPrefix(trimmed, "\t") {
+			return p.errorf(line, "tabs are not allowed in the indentation")
+		}
+		line.text = strings.TrimSpace(stripYAMLComment(trimmed))
+		switch {
+		case line.text == "":
+			continue
+		case line.text == "---" && len(p.lines) == 0:
+			continue
+		case line.text == "---" || line.text == "...":
+			return p.errorf(line, "multiple documents are not supported")
+		}
+		p.lines = append(p.lines, line)
+	}
+	return nil
+}
+
+// stripYAMLComment removes the comment at the end of a line, ignoring the # inside quotes
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// parseBlock parses the mapping or the sequence starting at the current line, indented by indent
+func (p *yamlParser) parseBlock(indent int) (interface{}, error) {
+	if isYAMLSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+// parseMapping parses the key-value pairs indented by indent
+func (p *yamlParser) parseMapping(indent int) (interface{}, error) {
+	mapping := map[string]interface{}{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && !isYAMLSequenceItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, p.errorf(line, "expected a key: value pair")
+		}
+		if _, exists := mapping[key]; exists {
+			return nil, p.errorf(line, "duplicate key %q", key)
+		}
+		p.pos++
+
+		if rest != "" {
+			value, err := p.parseScalar(line, rest)
+			if err != nil {
+				return nil, err
+			}
+			mapping[key] = value
+			continue
+		}
+		// the value is the block below the key: more indented, or a sequence with the same indentation
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && isYAMLSequenceItem(next.text)) {
+				value, err := p.parseBlock(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				mapping[key] = value
+				continue
+			}
+		}
+		mapping[key] = nil
+	}
+	return mapping, nil
+}
+
+// parseSequence parses the items indented by indent
+func (p *yamlParser) parseSequence(indent int) (interface{}, error) {
+	sequence := []interface{}{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLSequenceItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		item := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+
+		if item == "" {
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				value, err := p.parseBlock(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				sequence = append(sequence, value)
+			} else {
+				sequence = append(sequence, nil)
+			}
+			continue
+		}
+		if _, _, isPair := splitYAMLKey(item); isPair || isYAMLSequenceItem(item) {
+			// the item is a block starting on the line of the dash: parse it as if it were on its own line
+			p.lines[p.pos] = yamlLine{number: line.number, indent: indent + len(line.text) - len(item), text: item}
+			value, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+			continue
+		}
+		value, err := p.parseScalar(line, item)
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, value)
+		p.pos++
+	}
+	return sequence, nil
+}
+
+// parseScalar parses an inline value: a flow sequence, an empty flow mapping or a scalar
+func (p *yamlParser) parseScalar(line yamlLine, s string) (interface{}, error) {
+	switch {
+	case s == "{}":
+		return map[string]interface{}{}, nil
+	case strings.HasPrefix(s, "["):
+		if !strings.HasSuffix(s, "]") {
+			return nil, p.errorf(line, "unterminated flow sequence")
+		}
+		sequence := []interface{}{}
+		items, err := splitYAMLFlow(s[1 : len(s)-1])
+		if err != nil {
+			return nil, p.errorf(line, "%v", err)
+		}
+		for _, item := range items {
+			value, err := p.parseScalar(line, item)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+		}
+		return sequence, nil
+	case strings.HasPrefix(s, "{"), strings.HasPrefix(s, "&"), strings.HasPrefix(s, "*"), strings.HasPrefix(s, "!"),
+		s == "|", s == ">", strings.HasPrefix(s, "|-"), strings.HasPrefix(s, ">-"):
+		return nil, p.errorf(line, "unsupported YAML syntax %q", s)
+	case strings.HasPrefix(s, `"`):
+		value, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, p.errorf(line, "invalid double-quoted string %s", s)
+		}
+		return value, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return nil, p.errorf(line, "invalid single-quoted string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+
+	switch s {
+	case "null", "Null", "NULL", "~":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return s, nil
+}
+
+// errorf returns an error located at the given line
+func (p *yamlParser) errorf(line yamlLine, format string, args ...interface{}) error {
+	return fmt.Errorf("yaml: line %d: %s", line.number, fmt.Sprintf(format, args...))
+}
+
+// isYAMLSequenceItem tells if the text is an item of a block sequence
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey splits a "key: value" pair, the key can be quoted
+func splitYAMLKey(text string) (key, rest string, ok bool) {
+	if strings.HasPrefix(text, `"`) || strings.HasPrefix(text, "'") {
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 {
+			return "", "", false
+		}
+		key, text = text[1:end+1], text[end+2:]
+		if !strings.HasPrefix(text, ":") {
+			return "", "", false
+		}
+		text = text[1:]
+	} else {
+		i := strings.Index(text, ": ")
+		if i < 0 {
+			if !strings.HasSuffix(text, ":") {
+				return "", "", false
+			}
+			i = len(text) - 1
+		}
+		key, text = strings.TrimSpace(text[:i]), text[i+1:]
+		if key == "" || strings.ContainsAny(key[:1], "[{") {
+			return "", "", false
+		}
+	}
+	if text != "" && !strings.HasPrefix(text, " ") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(text), true
+}
+
+// splitYAMLFlow splits the items of a flow sequence, respecting the quotes
+func splitYAMLFlow(s string) ([]string, error) {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			return nil, fmt.Errorf("nested flow collections are not supported")
+		case c == ',':
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quoted string")
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" || len(items) > 0 {
+		items = append(items, last)
+	}
+	for _, item := range items {
+		if item == "" {
+			return nil, fmt.Errorf("empty item in flow sequence")
+		}
+	}
+	return items, nil
+}